@@ -1,7 +1,7 @@
 package advent
 
 func validPosition(p position, width, height int) bool {
-	return p.x >= 0 && p.x < width && p.y >= 0 && p.y < height
+	return p.X >= 0 && p.X < width && p.Y >= 0 && p.Y < height
 }
 
 // getBoardValue returns a rune/int/bool at x,y in the input or the empty value if out of bounds
@@ -1,6 +1,11 @@
 package color
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/charmbracelet/lipgloss"
+)
 
 func New(color string) lipgloss.Color {
 	return lipgloss.Color(color)
@@ -85,3 +90,79 @@ const (
 	Gray85 = lipgloss.Color("254")
 	Gray89 = lipgloss.Color("255")
 )
+
+// the 16 standard xterm colors, in RGB
+var standardRGB = [16][3]uint8{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// the levels of each component in the 6x6x6 color cube
+var cubeLevels = [6]uint8{0, 95, 135, 175, 215, 255}
+
+// RGB returns the red, green and blue components of an ANSI256 or #rrggbb hex color.
+// ok is false for colors that can't be converted, like lipgloss.NoColor
+func RGB(c lipgloss.TerminalColor) (r, g, b uint8, ok bool) {
+	lc, isColor := c.(lipgloss.Color)
+	if !isColor || lc == "" {
+		return 0, 0, 0, false
+	}
+	s := string(lc)
+	if s[0] == '#' {
+		if len(s) != 7 {
+			return 0, 0, 0, false
+		}
+		var rgb [3]uint8
+		for i := range rgb {
+			v, err := strconv.ParseUint(s[1+i*2:3+i*2], 16, 8)
+			if err != nil {
+				return 0, 0, 0, false
+			}
+			rgb[i] = uint8(v)
+		}
+		return rgb[0], rgb[1], rgb[2], true
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 || n > 255 {
+		return 0, 0, 0, false
+	}
+	switch {
+	case n < 16:
+		return standardRGB[n][0], standardRGB[n][1], standardRGB[n][2], true
+	case n < 232:
+		n -= 16
+		return cubeLevels[n/36], cubeLevels[(n/6)%6], cubeLevels[n%6], true
+	default:
+		gray := uint8(8 + (n-232)*10)
+		return gray, gray, gray, true
+	}
+}
+
+// Blend averages a group of colors into a single hex color. Colors that can't be
+// converted to RGB are skipped. If no colors can be blended, lipgloss.NoColor is returned
+func Blend(colors ...lipgloss.TerminalColor) lipgloss.TerminalColor {
+	var rt, gt, bt, count int
+	for _, c := range colors {
+		r, g, b, ok := RGB(c)
+		if !ok {
+			continue
+		}
+		rt, gt, bt = rt+int(r), gt+int(g), bt+int(b)
+		count++
+	}
+	if count == 0 {
+		return lipgloss.NoColor{}
+	}
+	if count == 1 {
+		// no need to convert a single color to hex
+		for _, c := range colors {
+			if _, _, _, ok := RGB(c); ok {
+				return c
+			}
+		}
+	}
+	return lipgloss.Color(fmt.Sprintf("#%02x%02x%02x", rt/count, gt/count, bt/count))
+}
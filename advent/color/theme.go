@@ -0,0 +1,182 @@
+package color
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme is a named palette. Each day's visual roles (walls, robots, paths, etc) map
+// to one of these entries so they can be restyled together
+type Theme struct {
+	Name string
+
+	Primary    lipgloss.TerminalColor // main board features, obstacles, boxes, antennas
+	Secondary  lipgloss.TerminalColor // paths, walls, antinodes
+	Accent     lipgloss.TerminalColor // the thing that moves, robots
+	Highlight  lipgloss.TerminalColor // guards, numbers
+	Muted      lipgloss.TerminalColor // unvisited or unimportant cells
+	Background lipgloss.TerminalColor // background for boundaries and overlays
+	Success    lipgloss.TerminalColor // correct results
+	Error      lipgloss.TerminalColor // incorrect results
+	Solution   lipgloss.TerminalColor // the solution text
+
+	// Ramp goes from low to high, for values like heights
+	Ramp []lipgloss.TerminalColor
+	// VisitedRamp is the Ramp for cells a solver has visited
+	VisitedRamp []lipgloss.TerminalColor
+	// Categories are distinct colors for things like garden regions
+	Categories []lipgloss.TerminalColor
+}
+
+// RampColor returns the color for a value on the theme's Ramp
+func (t Theme) RampColor(i int, visited bool) lipgloss.TerminalColor {
+	ramp := t.Ramp
+	if visited {
+		ramp = t.VisitedRamp
+	}
+	return ramp[min(max(i, 0), len(ramp)-1)]
+}
+
+// CategoryColor returns a color for category i, wrapping around if there are more categories than colors
+func (t Theme) CategoryColor(i int) lipgloss.TerminalColor {
+	if i < 0 {
+		i = -i
+	}
+	return t.Categories[i%len(t.Categories)]
+}
+
+func colors(values ...string) []lipgloss.TerminalColor {
+	c := make([]lipgloss.TerminalColor, len(values))
+	for i, v := range values {
+		c[i] = lipgloss.Color(v)
+	}
+	return c
+}
+
+func repeat(c lipgloss.TerminalColor, n int) []lipgloss.TerminalColor {
+	r := make([]lipgloss.TerminalColor, n)
+	for i := range r {
+		r[i] = c
+	}
+	return r
+}
+
+var (
+	// Dark is the default theme, made for dark terminal backgrounds
+	Dark = Theme{
+		Name:        "dark",
+		Primary:     LimeGreen,
+		Secondary:   lipgloss.Color("202"),
+		Accent:      BrightCyan,
+		Highlight:   lipgloss.Color("211"),
+		Muted:       Gray42,
+		Background:  DarkBlue,
+		Success:     lipgloss.Color("40"),
+		Error:       BrightRed,
+		Solution:    lipgloss.Color("86"),
+		Ramp:        colors("30", "31", "32", "33", "34", "35", "36", "37", "38", "39"),
+		VisitedRamp: colors("200", "201", "202", "203", "204", "205", "206", "207", "208", "209"),
+		Categories: colors("196", "202", "208", "214", "220", "226", "190", "154", "118", "82", "46", "48", "50",
+			"51", "45", "39", "33", "27", "21", "57", "93", "129", "165", "201", "205", "211"),
+	}
+
+	// Light is made for light terminal backgrounds
+	Light = Theme{
+		Name:        "light",
+		Primary:     lipgloss.Color("28"),
+		Secondary:   lipgloss.Color("166"),
+		Accent:      lipgloss.Color("25"),
+		Highlight:   lipgloss.Color("161"),
+		Muted:       Gray62,
+		Background:  lipgloss.Color("189"),
+		Success:     lipgloss.Color("22"),
+		Error:       lipgloss.Color("160"),
+		Solution:    lipgloss.Color("30"),
+		Ramp:        colors("153", "117", "111", "75", "69", "33", "27", "26", "20", "18"),
+		VisitedRamp: colors("218", "211", "205", "204", "198", "197", "161", "125", "89", "53"),
+		Categories: colors("160", "166", "172", "136", "100", "64", "28", "29", "30", "31", "25", "19", "55",
+			"91", "127", "125", "88", "94", "58", "22", "23", "24", "18", "54", "90", "126"),
+	}
+
+	// HighContrast uses only the brightest standard colors
+	HighContrast = Theme{
+		Name:        "high-contrast",
+		Primary:     BrightGreen,
+		Secondary:   BrightYellow,
+		Accent:      BrightCyan,
+		Highlight:   BrightMagenta,
+		Muted:       White,
+		Background:  Blue,
+		Success:     BrightGreen,
+		Error:       BrightRed,
+		Solution:    BrightWhite,
+		Ramp:        colors("4", "4", "12", "12", "6", "6", "14", "14", "15", "15"),
+		VisitedRamp: colors("1", "1", "9", "9", "3", "3", "11", "11", "15", "15"),
+		Categories:  colors("9", "10", "11", "12", "13", "14"),
+	}
+
+	// ColorBlind uses the Okabe-Ito palette, which is distinguishable with the common forms of color blindness
+	ColorBlind = Theme{
+		Name:        "color-blind",
+		Primary:     lipgloss.Color("#009E73"),
+		Secondary:   lipgloss.Color("#E69F00"),
+		Accent:      lipgloss.Color("#56B4E9"),
+		Highlight:   lipgloss.Color("#CC79A7"),
+		Muted:       Gray50,
+		Background:  lipgloss.Color("#0072B2"),
+		Success:     lipgloss.Color("#009E73"),
+		Error:       lipgloss.Color("#D55E00"),
+		Solution:    lipgloss.Color("#F0E442"),
+		Ramp:        colors("#08306B", "#08519C", "#2171B5", "#4292C6", "#6BAED6", "#9ECAE1", "#C6DBEF", "#DEEBF7", "#F0F7FF", "#FFFFFF"),
+		VisitedRamp: colors("#7F2704", "#A63603", "#D94801", "#F16913", "#FD8D3C", "#FDAE6B", "#FDD0A2", "#FEE6CE", "#FFF5EB", "#FFFFFF"),
+		Categories:  colors("#E69F00", "#56B4E9", "#009E73", "#F0E442", "#0072B2", "#D55E00", "#CC79A7"),
+	}
+
+	// Plain has no colors at all, for NO_COLOR and terminals without color support
+	Plain = Theme{
+		Name:        "plain",
+		Primary:     lipgloss.NoColor{},
+		Secondary:   lipgloss.NoColor{},
+		Accent:      lipgloss.NoColor{},
+		Highlight:   lipgloss.NoColor{},
+		Muted:       lipgloss.NoColor{},
+		Background:  lipgloss.NoColor{},
+		Success:     lipgloss.NoColor{},
+		Error:       lipgloss.NoColor{},
+		Solution:    lipgloss.NoColor{},
+		Ramp:        repeat(lipgloss.NoColor{}, 10),
+		VisitedRamp: repeat(lipgloss.NoColor{}, 10),
+		Categories:  repeat(lipgloss.NoColor{}, 1),
+	}
+)
+
+// Themes are all the built in themes by name
+var Themes = map[string]Theme{
+	Dark.Name:         Dark,
+	Light.Name:        Light,
+	HighContrast.Name: HighContrast,
+	ColorBlind.Name:   ColorBlind,
+	Plain.Name:        Plain,
+}
+
+// ThemeNames returns the sorted names of the built in themes
+func ThemeNames() []string {
+	names := make([]string, 0, len(Themes))
+	for name := range Themes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// FindTheme looks up a built in theme by name
+func FindTheme(name string) (Theme, error) {
+	theme, ok := Themes[name]
+	if !ok {
+		return Theme{}, fmt.Errorf("unknown theme %s, expected one of %s", name, strings.Join(ThemeNames(), ", "))
+	}
+	return theme, nil
+}
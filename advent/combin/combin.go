@@ -0,0 +1,73 @@
+// Package combin lazily enumerates products and permutations so searches can stop early
+// or skip whole branches.
+package combin
+
+import "iter"
+
+// Product yields every sequence of n values, like counting in base len(values).
+// The yielded slice is reused, so clone it to keep it.
+func Product[T any](values []T, n int) iter.Seq[[]T] {
+	return ProductFunc(values, n, nil)
+}
+
+// ProductFunc is like Product, but calls keep with every prefix as it grows by one value.
+// If keep returns false, no sequences starting with that prefix are yielded.
+func ProductFunc[T any](values []T, n int, keep func(prefix []T) bool) iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		seq := make([]T, 0, n)
+		var next func() bool
+		next = func() bool {
+			if len(seq) == n {
+				return yield(seq)
+			}
+			for _, v := range values {
+				seq = append(seq, v)
+				if keep == nil || keep(seq) {
+					if !next() {
+						return false
+					}
+				}
+				seq = seq[:len(seq)-1]
+			}
+			return true
+		}
+		next()
+	}
+}
+
+// Permutations yields every ordering of values.
+// The yielded slice is reused, so clone it to keep it.
+func Permutations[T any](values []T) iter.Seq[[]T] {
+	return PermutationsFunc(values, nil)
+}
+
+// PermutationsFunc is like Permutations, but calls keep with every prefix as it grows by one value.
+// If keep returns false, no orderings starting with that prefix are yielded.
+func PermutationsFunc[T any](values []T, keep func(prefix []T) bool) iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		seq := make([]T, 0, len(values))
+		used := make([]bool, len(values))
+		var next func() bool
+		next = func() bool {
+			if len(seq) == len(values) {
+				return yield(seq)
+			}
+			for i, v := range values {
+				if used[i] {
+					continue
+				}
+				used[i] = true
+				seq = append(seq, v)
+				if keep == nil || keep(seq) {
+					if !next() {
+						return false
+					}
+				}
+				seq = seq[:len(seq)-1]
+				used[i] = false
+			}
+			return true
+		}
+		next()
+	}
+}
@@ -0,0 +1,43 @@
+package combin
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestProduct(t *testing.T) {
+	var got [][]int
+	for seq := range Product([]int{0, 1}, 2) {
+		got = append(got, slices.Clone(seq))
+	}
+	want := [][]int{{0, 0}, {0, 1}, {1, 0}, {1, 1}}
+	if !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("Product() = %v, want %v", got, want)
+	}
+
+	// skip anything starting with 1
+	count := 0
+	for range ProductFunc([]int{0, 1, 2}, 3, func(prefix []int) bool { return prefix[0] != 1 }) {
+		count++
+	}
+	if count != 18 {
+		t.Errorf("ProductFunc() yielded %d, want 18", count)
+	}
+}
+
+func TestPermutations(t *testing.T) {
+	count := 0
+	for seq := range Permutations([]rune("abcd")) {
+		count++
+		if count == 1 && string(seq) != "abcd" {
+			t.Errorf("first permutation = %s, want abcd", string(seq))
+		}
+		if count == 10 {
+			// stop early
+			break
+		}
+	}
+	if count != 10 {
+		t.Errorf("Permutations() stopped after %d, want 10", count)
+	}
+}
@@ -0,0 +1,96 @@
+package advent
+
+// Component is a group of connected positions on a grid with the same value
+type Component[T comparable] struct {
+	Label     int
+	Value     T
+	Area      int
+	Perimeter int
+	// Sides is the number of straight fence sides around the component, counted from its corners
+	Sides int
+	// Min and Max are the top left and bottom right corners of the bounding box
+	Min   position
+	Max   position
+	Cells []position
+}
+
+// FloodFill returns every position reachable from start moving up, down, left and right
+// between positions where connected returns true, in the order they were found
+func (g *Grid[T]) FloodFill(start position, connected func(from, to position) bool) []position {
+	if !g.InBounds(start) {
+		return nil
+	}
+	seen := NewGrid[bool](g.Size())
+	seen.Set(start, true)
+	filled := []position{start}
+	for i := 0; i < len(filled); i++ {
+		from := filled[i]
+		for to := range g.Neighbors(from, cardinalDirections) {
+			if !seen.Get(to) && connected(from, to) {
+				seen.Set(to, true)
+				filled = append(filled, to)
+			}
+		}
+	}
+	return filled
+}
+
+// Components labels each group of connected positions with the same value. The label grid holds
+// the index of each position's component. The grid itself isn't changed.
+func (g *Grid[T]) Components() (labels *Grid[int], components []Component[T]) {
+	labels = NewGrid[int](g.Size())
+	labels.Fill(-1)
+
+	for start, value := range g.All() {
+		if labels.Get(start) != -1 {
+			continue
+		}
+		component := Component[T]{Label: len(components), Value: value, Min: start, Max: start}
+		component.Cells = g.FloodFill(start, func(from, to position) bool {
+			return g.Get(to) == value
+		})
+		for _, p := range component.Cells {
+			labels.Set(p, component.Label)
+			component.Min = position{X: min(component.Min.X, p.X), Y: min(component.Min.Y, p.Y)}
+			component.Max = position{X: max(component.Max.X, p.X), Y: max(component.Max.Y, p.Y)}
+		}
+		components = append(components, component)
+	}
+
+	for i := range components {
+		c := &components[i]
+		c.Area = len(c.Cells)
+		inside := func(p position) bool {
+			label, ok := labels.Lookup(p)
+			return ok && label == c.Label
+		}
+		for _, p := range c.Cells {
+			for _, dir := range cardinalDirections {
+				if !inside(p.Move(dir)) {
+					c.Perimeter++
+				}
+			}
+			c.Sides += corners(p, inside)
+		}
+	}
+
+	return labels, components
+}
+
+// corners counts the corners of a cell on the outline of a shape. A shape has as many sides as corners.
+//
+//	outer corner   inner corner
+//	 . .            A .
+//	 A .            A A
+func corners(p position, inside func(p position) bool) int {
+	count := 0
+	for _, dir := range cardinalDirections {
+		next := dir.TurnRight()
+		a, b := inside(p.Move(dir)), inside(p.Move(next))
+		diagonal := inside(p.Move(dir).Move(next))
+		if (!a && !b) || (a && b && !diagonal) {
+			count++
+		}
+	}
+	return count
+}
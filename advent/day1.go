@@ -1,19 +1,25 @@
 package advent
 
 import (
-	"bufio"
 	"fmt"
-	"math"
-	"os"
+	"regexp"
 	"slices"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sirgwain/advent-of-code-2024/advent/mathx"
+	"github.com/sirgwain/advent-of-code-2024/advent/parse"
+	"github.com/sirgwain/advent-of-code-2024/advent/tui"
 )
 
 type Day1 struct {
+	*Options
 }
 
 func (d *Day1) Run(part int, filename string, opts ...Option) error {
+	d.Options = newRun(opts...)
 	switch part {
 	case 1:
 		return d.part1(filename)
@@ -24,39 +30,54 @@ func (d *Day1) Run(part int, filename string, opts ...Option) error {
 	}
 }
 
+// Validate reads the input file and checks its format without solving
+func (d *Day1) Validate(filename string) error {
+	_, _, err := d.readInput(filename)
+	return err
+}
+
+var day1Line = regexp.MustCompile(`^\d+\s+\d+$`)
+
+// readInput reads the two location lists, one pair of numbers per line
 func (d *Day1) readInput(filename string) ([]int, []int, error) {
-	file, err := os.Open(filename)
+	file, err := parse.ReadFile(filename)
 	if err != nil {
-		return nil, nil, fmt.Errorf("error opening file: %w", err)
+		return nil, nil, err
 	}
-	defer file.Close()
 
 	var slice1, slice2 []int
-
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := scanner.Text()
-
-		parts := strings.Fields(line)
-		if len(parts) != 2 {
-			return nil, nil, fmt.Errorf("line doesn't contain two numbers: %s", line)
+	for _, line := range file.Lines {
+		if err := line.Expect(day1Line); err != nil {
+			return nil, nil, err
 		}
-
-		num1, err1 := strconv.Atoi(parts[0])
-		num2, err2 := strconv.Atoi(parts[1])
-		if err1 != nil || err2 != nil {
-			return nil, nil, fmt.Errorf("error parsing numbers on line: %s", line)
+		nums, err := line.IntsN(2)
+		if err != nil {
+			return nil, nil, err
 		}
 
-		slice1 = append(slice1, num1)
-		slice2 = append(slice2, num2)
+		slice1 = append(slice1, nums[0])
+		slice2 = append(slice2, nums[1])
 	}
 
-	if err := scanner.Err(); err != nil {
-		return nil, nil, fmt.Errorf("error reading file: %w", err)
+	return slice1, slice2, nil
+}
+
+// RunVisual is like Run, but it starts a bubbletea program and animates the lists in a goroutine
+func (d *Day1) RunVisual(part int, filename string, opts ...Option) error {
+	d.Options = newRun(opts...)
+	slice1, slice2, err := d.readInput(filename)
+	if err != nil {
+		return err
 	}
 
-	return slice1, slice2, nil
+	switch part {
+	case 1:
+		return d.part1Visual(slice1, slice2)
+	case 2:
+		return d.part2Visual(slice1, slice2)
+	default:
+		return fmt.Errorf("part %d not valid", part)
+	}
 }
 
 func (d *Day1) part1(filename string) error {
@@ -68,11 +89,7 @@ func (d *Day1) part1(filename string) error {
 
 	slices.Sort(slice1)
 	slices.Sort(slice2)
-
-	dist := 0
-	for i := range slice1 {
-		dist += int(math.Abs(float64(slice1[i] - slice2[i])))
-	}
+	dist := day1Distance(slice1, slice2)
 
 	// Output the dist
 	fmt.Printf("day1: %s dist = %d\n", filename, dist)
@@ -85,17 +102,220 @@ func (d *Day1) part2(filename string) error {
 		return err
 	}
 
+	similarity := day1Similarity(slice1, day1Occurances(slice2))
+
+	// Output the dist
+	fmt.Printf("day2: %s similarity = %d\n", filename, similarity)
+
+	return nil
+}
+
+func (d *Day1) part1Visual(slice1, slice2 []int) error {
+	// create a bubbletea program
+	p := tui.NewViewportProgram(tui.NewModel("Day 1 - Part 1"))
+
+	// find the solution so we can hide it from the output
+	solution := day1Distance(slices.Sorted(slices.Values(slice1)), slices.Sorted(slices.Values(slice2)))
+	view := newDay1View(slice1, slice2)
+
+	// only draw a frame every few steps, the real input has 1000 lines
+	frameEvery := max(1, len(slice1)/100)
+
+	go func() {
+		// insertion sort both lists so you can watch each value move into place
+		for sorted := 1; sorted < len(slice1); sorted++ {
+			inserted1 := day1Insert(slice1, sorted)
+			inserted2 := day1Insert(slice2, sorted)
+			if sorted%frameEvery == 0 {
+				p.Send(tui.UpdateViewport(view.sorting(slice1, slice2, sorted+1, inserted1, inserted2), view.width()))
+				d.sleep()
+			}
+		}
+
+		// pair them up smallest to largest and add up the distances
+		dist := 0
+		for i := range slice1 {
+			dist += mathx.Abs(slice1[i] - slice2[i])
+			if (i+1)%frameEvery == 0 || i == len(slice1)-1 {
+				p.Send(tui.UpdateViewport(view.pairs(slice1, slice2, i+1, d.redaction().render(dist, solution)), view.width()))
+				d.sleep()
+			}
+		}
+		if d.RedactSolution {
+			p.Send(tui.Reveal(tui.UpdateViewport(view.pairs(slice1, slice2, len(slice1), noRedaction.render(dist, solution)), view.width())))
+		}
+	}()
+
+	if _, err := p.Run(); err != nil {
+		return fmt.Errorf("could not start program: %v", err)
+	}
+
+	fmt.Printf("Total Distance: %s\n", solutionStyle.Render(strconv.Itoa(solution)))
+	return nil
+}
+
+func (d *Day1) part2Visual(slice1, slice2 []int) error {
+	// create a bubbletea program
+	p := tui.NewViewportProgram(tui.NewModel("Day 1 - Part 2"))
+
+	occurances := day1Occurances(slice2)
+	solution := day1Similarity(slice1, occurances)
+	view := newDay1View(slice1, slice2)
+
+	frameEvery := max(1, len(slice1)/100)
+
+	go func() {
+		// add up each left value's contribution, one row of the histogram at a time
+		similarity := 0
+		for i, val := range slice1 {
+			similarity += val * occurances[val]
+			if (i+1)%frameEvery == 0 || i == len(slice1)-1 {
+				p.Send(tui.UpdateViewport(view.histogram(slice1, occurances, i+1, d.redaction().render(similarity, solution)), view.width()))
+				d.sleep()
+			}
+		}
+		if d.RedactSolution {
+			p.Send(tui.Reveal(tui.UpdateViewport(view.histogram(slice1, occurances, len(slice1), noRedaction.render(similarity, solution)), view.width())))
+		}
+	}()
+
+	if _, err := p.Run(); err != nil {
+		return fmt.Errorf("could not start program: %v", err)
+	}
+
+	fmt.Printf("Similarity: %s\n", solutionStyle.Render(strconv.Itoa(solution)))
+	return nil
+}
+
+// sleep waits for the Delay option between frames
+func (d *Day1) sleep() {
+	if d.Delay != 0 {
+		time.Sleep(time.Duration(d.Delay) * time.Millisecond)
+	}
+}
+
+// day1Distance adds up the distance between each pair of values in two sorted lists
+func day1Distance(slice1, slice2 []int) int {
+	dist := 0
+	for i := range slice1 {
+		dist += mathx.Abs(slice1[i] - slice2[i])
+	}
+	return dist
+}
+
+// day1Occurances counts how many times each value appears in a list
+func day1Occurances(slice2 []int) map[int]int {
 	slice2Occurances := make(map[int]int, len(slice2))
 	for _, val := range slice2 {
 		slice2Occurances[val]++
 	}
+	return slice2Occurances
+}
 
+// day1Similarity adds up each left value times the number of times it's in the right list
+func day1Similarity(slice1 []int, slice2Occurances map[int]int) int {
 	similarity := 0
 	for _, val := range slice1 {
 		similarity += val * slice2Occurances[val]
 	}
-	// Output the dist
-	fmt.Printf("day2: %s similarity = %d\n", filename, similarity)
+	return similarity
+}
 
-	return nil
+// day1Insert moves s[n] into place in the sorted s[:n] and returns its new index
+func day1Insert(s []int, n int) int {
+	i := n
+	for ; i > 0 && s[i-1] > s[i]; i-- {
+		s[i-1], s[i] = s[i], s[i-1]
+	}
+	return i
+}
+
+// day1BarWidth is the width of the longest bar in the visualizations
+const day1BarWidth = 40
+
+// day1View draws the lists as columns, sized to fit the largest values
+type day1View struct {
+	numWidth int
+	maxDist  int
+	maxCount int
+}
+
+func newDay1View(slice1, slice2 []int) day1View {
+	v := day1View{numWidth: 1, maxDist: 1, maxCount: 1}
+	for _, val := range append(slices.Clone(slice1), slice2...) {
+		v.numWidth = max(v.numWidth, mathx.NumDigits(val))
+	}
+
+	sorted1, sorted2 := slices.Sorted(slices.Values(slice1)), slices.Sorted(slices.Values(slice2))
+	for i := range sorted1 {
+		v.maxDist = max(v.maxDist, mathx.Abs(sorted1[i]-sorted2[i]))
+	}
+	for _, count := range day1Occurances(slice2) {
+		v.maxCount = max(v.maxCount, count)
+	}
+	return v
+}
+
+// width is the width of the widest line, two numbers and a bar
+func (v day1View) width() int {
+	return v.numWidth*4 + day1BarWidth + 8
+}
+
+func (v day1View) number(val int, style lipgloss.Style) string {
+	return style.Render(fmt.Sprintf("%*d", v.numWidth, val))
+}
+
+// bar draws a bar value/maximum of the full width
+func (v day1View) bar(value, maximum int) string {
+	return barStyle.Render(strings.Repeat("█", value*day1BarWidth/maximum))
+}
+
+// sorting shows both lists with the first sorted values in place and the values just inserted highlighted
+func (v day1View) sorting(slice1, slice2 []int, sorted, inserted1, inserted2 int) string {
+	style := func(i, inserted int) lipgloss.Style {
+		switch {
+		case i == inserted:
+			return insertedStyle
+		case i < sorted:
+			return sortedStyle
+		}
+		return unsortedStyle
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "sorted %d/%d\n\n", sorted, len(slice1))
+	for i := range slice1 {
+		fmt.Fprintf(&sb, "%s   %s\n", v.number(slice1[i], style(i, inserted1)), v.number(slice2[i], style(i, inserted2)))
+	}
+	return sb.String()
+}
+
+// pairs shows the sorted lists side by side with a bar for the distance of the first paired pairs
+func (v day1View) pairs(slice1, slice2 []int, paired int, dist string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Total Distance: %s\n\n", dist)
+	for i := range slice1 {
+		if i >= paired {
+			fmt.Fprintf(&sb, "%s   %s\n", v.number(slice1[i], unsortedStyle), v.number(slice2[i], unsortedStyle))
+			continue
+		}
+		distance := mathx.Abs(slice1[i] - slice2[i])
+		fmt.Fprintf(&sb, "%s   %s %s %d\n", v.number(slice1[i], sortedStyle), v.number(slice2[i], sortedStyle), v.bar(distance, v.maxDist), distance)
+	}
+	return sb.String()
+}
+
+// histogram shows how many times each left value is in the right list, and what it adds to the similarity
+func (v day1View) histogram(slice1 []int, occurances map[int]int, counted int, similarity string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Similarity: %s\n\n", similarity)
+	for i, val := range slice1 {
+		if i >= counted {
+			fmt.Fprintf(&sb, "%s\n", v.number(val, unsortedStyle))
+			continue
+		}
+		count := occurances[val]
+		fmt.Fprintf(&sb, "%s x %2d %s %s\n", v.number(val, sortedStyle), count, v.bar(count, v.maxCount), numberStyle.Render(strconv.Itoa(val*count)))
+	}
+	return sb.String()
 }
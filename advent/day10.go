@@ -2,10 +2,9 @@ package advent
 
 import (
 	"fmt"
-	"strconv"
 	"strings"
 
-	"github.com/charmbracelet/lipgloss"
+	"github.com/sirgwain/advent-of-code-2024/advent/search"
 	"github.com/sirgwain/advent-of-code-2024/advent/tui"
 )
 
@@ -14,45 +13,15 @@ type Day10 struct {
 }
 
 type day10Board struct {
-	board     [][]int
-	width     int
-	height    int
+	board     *Grid[int]
 	trailEnd  int
-	visited   [][]bool
+	visits    *Grid[int]
 	solutions map[position]map[position]int
 	solution1 int
 	solution2 int
 	onStep    func(p position)
 }
 
-// base height styles before rendering the board. These start at dark blue and get lighter
-var heightRenders = []string{
-	lipgloss.NewStyle().Foreground(lipgloss.Color("30")).Render("0"),
-	lipgloss.NewStyle().Foreground(lipgloss.Color("31")).Render("1"),
-	lipgloss.NewStyle().Foreground(lipgloss.Color("32")).Render("2"),
-	lipgloss.NewStyle().Foreground(lipgloss.Color("33")).Render("3"),
-	lipgloss.NewStyle().Foreground(lipgloss.Color("34")).Render("4"),
-	lipgloss.NewStyle().Foreground(lipgloss.Color("35")).Render("5"),
-	lipgloss.NewStyle().Foreground(lipgloss.Color("36")).Render("6"),
-	lipgloss.NewStyle().Foreground(lipgloss.Color("37")).Render("7"),
-	lipgloss.NewStyle().Foreground(lipgloss.Color("38")).Render("8"),
-	lipgloss.NewStyle().Foreground(lipgloss.Color("39")).Render("9"),
-}
-
-// styles for visited locations, these are pink and orange
-var heightVisitedRenders = []string{
-	lipgloss.NewStyle().Foreground(lipgloss.Color("200")).Render("0"),
-	lipgloss.NewStyle().Foreground(lipgloss.Color("201")).Render("1"),
-	lipgloss.NewStyle().Foreground(lipgloss.Color("202")).Render("2"),
-	lipgloss.NewStyle().Foreground(lipgloss.Color("203")).Render("3"),
-	lipgloss.NewStyle().Foreground(lipgloss.Color("204")).Render("4"),
-	lipgloss.NewStyle().Foreground(lipgloss.Color("205")).Render("5"),
-	lipgloss.NewStyle().Foreground(lipgloss.Color("206")).Render("6"),
-	lipgloss.NewStyle().Foreground(lipgloss.Color("207")).Render("7"),
-	lipgloss.NewStyle().Foreground(lipgloss.Color("208")).Render("8"),
-	lipgloss.NewStyle().Foreground(lipgloss.Color("209")).Render("9"),
-}
-
 // Run is the main entry point for a day. It reads the input file and runs the part
 func (d *Day10) Run(part int, filename string, opts ...Option) error {
 	d.Options = newRun(opts...)
@@ -71,6 +40,12 @@ func (d *Day10) Run(part int, filename string, opts ...Option) error {
 	}
 }
 
+// Validate reads the input file and checks its format without solving
+func (d *Day10) Validate(filename string) error {
+	_, err := d.readInput(filename)
+	return err
+}
+
 // RunVisual is like Run, but it starts a bubbletea program and runs the solver in a goroutine
 func (d *Day10) RunVisual(part int, filename string, opts ...Option) error {
 	d.Options = newRun(opts...)
@@ -89,61 +64,61 @@ func (d *Day10) RunVisual(part int, filename string, opts ...Option) error {
 	}
 }
 
-func (d *Day10) readInput(filename string) ([][]int, error) {
-	return readInputAsIntBoard(filename)
+func (d *Day10) readInput(filename string) (*Grid[int], error) {
+	return readInputAsDigitGrid(filename)
 }
 
-func (d *Day10) part1(input [][]int) error {
+func (d *Day10) part1(input *Grid[int]) error {
 	board := day10Board{
 		board:    input,
 		trailEnd: 9,
-		height:   len(input),
-		width:    len(input[0]),
 	}
 	fmt.Println(board.view())
 	board.findTrails()
-	fmt.Println(board.viewSolution(false))
+	fmt.Println(board.viewSolution(noRedaction))
 
 	return nil
 }
 
-func (d *Day10) part2(input [][]int) error {
+func (d *Day10) part2(input *Grid[int]) error {
 	// part1 and part2 are the same for this day
 	d.part1(input)
 	return nil
 }
 
-func (d *Day10) part2Visual(input [][]int) error {
-	// create a bubbletea program
-	p := tui.NewViewportProgram(tui.NewModel("Day 10"))
-
+func (d *Day10) part2Visual(input *Grid[int]) error {
 	// find the solution so we can hide it from the output
-	silentBoard := day10Board{board: duplicate2DSlice(input),
+	silentBoard := day10Board{board: input.Clone(),
 		trailEnd: 9,
-		height:   len(input),
-		width:    len(input[0]),
 	}
 	silentBoard.findTrails()
 
 	// create a board with the solution numbers there already so we can redact them as we get close
 	board := day10Board{board: input,
 		trailEnd:  9,
-		height:    len(input),
-		width:     len(input[0]),
 		solution1: silentBoard.trails(),
 		solution2: silentBoard.distinctTrails(),
 	}
 
+	// create a bubbletea program, the gate pauses the solver while we inspect the board
+	gate := tui.NewGate()
+	p := tui.NewViewportProgram(tui.NewModel("Day 10").
+		WithRenderMode(d.RenderMode).
+		WithInspector(inspector(board.inspect), gate))
+
 	// run the solver in a gouroutine and Send a message to the bubbletea program to update the viewport
 	// on each step
-	width := len(board.board[0])
 	go func() {
+		defer gate.Done()
 		// update the UI
 		board.onStep = func(pos position) {
-			content := fmt.Sprintf("%s\n%s", board.view(), board.viewSolution(d.RedactSolution))
-			p.Send(tui.UpdateViewport(content, width))
+			p.Send(tui.UpdateBoard(&board, board.viewSolution(d.redaction())))
+			gate.Step()
 		}
 		board.findTrails()
+		if d.RedactSolution {
+			p.Send(tui.Reveal(tui.UpdateBoard(&board, board.viewSolution(noRedaction))))
+		}
 	}()
 
 	// execute the bubbletea program. This will block until the user pressed q or esc
@@ -153,7 +128,7 @@ func (d *Day10) part2Visual(input [][]int) error {
 
 	// output the board and final result before exiting the program
 	fmt.Println(board.view())
-	fmt.Println(board.viewSolution(false))
+	fmt.Println(board.viewSolution(noRedaction))
 	return nil
 }
 
@@ -163,48 +138,37 @@ func (b *day10Board) findTrails() {
 	// a count for each time we arrive
 	b.solutions = map[position]map[position]int{}
 
-	// keep track of what positions we've visited just for UI updates to look pretty
-	b.visited = make([][]bool, b.height)
-	for y := 0; y < b.height; y++ {
-		b.visited[y] = make([]bool, b.width)
-	}
-
-	for y := 0; y < b.height; y++ {
-		for x := 0; x < b.width; x++ {
-			// for every board value of 0, recursively find all trails that end at 9
-			if getBoardValue(x, y, b.board) == 0 {
-				start := position{x, y}
-				trailEnds := make(map[position]int)
-
-				// recursively find the trail
-				b.findTrail(start, 0, trailEnds)
-
-				// if our findTrail came with solutions, add them to our solutions map
-				// for this starting position
-				if len(trailEnds) > 0 {
-					b.solutions[start] = trailEnds
-				}
+	// keep track of how often we've visited each position for UI updates to look pretty
+	b.visits = NewGrid[int](b.board.Size())
+
+	// trails only go up one height at a time
+	uphill := gridNeighbors(b.board, cardinalDirections, func(from, to position) bool {
+		return b.board.Get(to) == b.board.Get(from)+1
+	})
+	onVisit := search.WithOnVisit(func(pos position, cost int) {
+		b.visits.Set(pos, b.visits.Get(pos)+1)
+		if b.onStep != nil {
+			b.onStep(pos)
+		}
+	})
+
+	// for every board value of 0, search for all trails that end at 9
+	for _, start := range b.board.FindAll(0) {
+		// every step goes up by one, so every trail to a trail end is the same length and
+		// all the trails are shortest paths
+		result := search.BFS(start, uphill, nil, onVisit)
+
+		trailEnds := make(map[position]int)
+		for pos := range result.Costs {
+			if b.board.Get(pos) == b.trailEnd {
+				trailEnds[pos] = result.CountPaths(pos)
 			}
 		}
-	}
-}
 
-// findTrail will search for the next height
-func (b day10Board) findTrail(pos position, currentHeight int, trailHeads map[position]int) {
-	b.visited[pos.y][pos.x] = true
-	if b.onStep != nil {
-		b.onStep(pos)
-	}
-	if currentHeight == b.trailEnd {
-		trailHeads[pos]++
-		return
-	}
-
-	// try each four positions
-	for _, dir := range cardinalDirections {
-		testPos := pos.addDirection(dir)
-		if validPosition(testPos, b.width, b.height) && getBoardValue(testPos.x, testPos.y, b.board) == currentHeight+1 {
-			b.findTrail(testPos, currentHeight+1, trailHeads)
+		// if our search came with solutions, add them to our solutions map
+		// for this starting position
+		if len(trailEnds) > 0 {
+			b.solutions[start] = trailEnds
 		}
 	}
 }
@@ -213,19 +177,43 @@ func (b day10Board) findTrail(pos position, currentHeight int, trailHeads map[po
 // This is called by the UI for every step to update
 // it will also be called at the end of the program to display the final board
 func (b day10Board) view() string {
+	return b.board.Render(func(p position, height int) string {
+		// we have 10 height styles of increasing color, one slice for visited positions and one for unvisited
+		// render each number as a style
+		if b.visits != nil && b.visits.Get(p) > 0 {
+			return heightVisitedRenders[height]
+		}
+		return heightRenders[height]
+	})
+}
+
+// Size and Cell make the board a tui.Board
+func (b *day10Board) Size() (width, height int) {
+	return b.board.Size()
+}
+
+func (b *day10Board) Cell(x, y int) tui.Cell {
+	height := b.board.Get(position{X: x, Y: y})
+	if b.visits != nil && b.visits.Get(position{X: x, Y: y}) > 0 {
+		return tui.Cell{Char: rune('0' + height), Fg: theme.RampColor(height, true)}
+	}
+	return tui.Cell{Char: rune('0' + height), Fg: theme.RampColor(height, false)}
+}
+
+// inspect describes a position's height, how often the solver has visited it and
+// the trails found from it
+func (b *day10Board) inspect(p position) string {
 	var sb strings.Builder
-	board := b.board
-	for y := 0; y < len(board); y++ {
-		for x := 0; x < len(board[y]); x++ {
-			// we have 10 height styles of increasing color, one slice for visited positions and one for unvisited
-			// render each number as a style
-			if b.visited != nil && b.visited[y][x] {
-				sb.WriteString(heightVisitedRenders[board[y][x]])
-			} else {
-				sb.WriteString(heightRenders[board[y][x]])
-			}
+	fmt.Fprintf(&sb, "height: %d\n", b.board.Get(p))
+	if b.visits != nil {
+		fmt.Fprintf(&sb, "visits: %d\n", b.visits.Get(p))
+	}
+	if trailEnds, ok := b.solutions[p]; ok {
+		paths := 0
+		for _, count := range trailEnds {
+			paths += count
 		}
-		sb.WriteString("\n")
+		fmt.Fprintf(&sb, "\ntrailhead\ntrail ends: %d\npaths: %d\n", len(trailEnds), paths)
 	}
 	return sb.String()
 }
@@ -253,18 +241,8 @@ func (b day10Board) distinctTrails() int {
 }
 
 // viewSolution renders the solution for part 1 and 2 as a string
-func (b day10Board) viewSolution(redactSolution bool) string {
-	// hide the solution if it's within 100
-	totalTrails := b.trails()
-	distinctTrails := b.distinctTrails()
-	trails := solutionStyle.Render(strconv.Itoa(totalTrails))
-	if redactSolution && float64(float64(totalTrails)/float64(b.solution1)) > .75 {
-		trails = solutionStyle.Render("<redacted>")
-	}
-	distinctPaths := solutionStyle.Render(strconv.Itoa(distinctTrails))
-	if redactSolution && float64(float64(distinctTrails)/float64(b.solution2)) > .75 {
-		distinctPaths = solutionStyle.Render("<redacted>")
-	}
-
-	return fmt.Sprintf("Trails: %s, Distinct Paths: %s\n", trails, distinctPaths)
+func (b day10Board) viewSolution(redact redaction) string {
+	return fmt.Sprintf("Trails: %s, Distinct Paths: %s\n",
+		redact.render(b.trails(), b.solution1),
+		redact.render(b.distinctTrails(), b.solution2))
 }
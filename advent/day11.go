@@ -1,11 +1,17 @@
 package advent
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
 	"math"
-	"os"
+	"math/big"
+	"regexp"
 	"strconv"
-	"strings"
+
+	"github.com/sirgwain/advent-of-code-2024/advent/mathx"
+	"github.com/sirgwain/advent-of-code-2024/advent/memo"
+	"github.com/sirgwain/advent-of-code-2024/advent/parse"
 )
 
 type Day11 struct {
@@ -13,7 +19,13 @@ type Day11 struct {
 
 	// keep track of how many stones a stone blinked a
 	// certain number of times creates
-	stoneCache map[day11CacheEntry]int
+	stoneCache *memo.Memo[day11CacheEntry, int]
+
+	// the same with big integers, for when there are too many stones for an int
+	bigStoneCache *memo.Memo[day11CacheEntry, *big.Int]
+
+	// set if a count in the stoneCache overflowed
+	overflowed bool
 }
 
 type day11CacheEntry struct {
@@ -39,24 +51,34 @@ func (d *Day11) Run(part int, filename string, opts ...Option) error {
 	}
 }
 
+// Validate reads the input file and checks its format without solving
+func (d *Day11) Validate(filename string) error {
+	_, err := d.readInput(filename)
+	return err
+}
+
+var day11Line = regexp.MustCompile(`^\d+( \d+)*$`)
+
+// readInput reads the stones, a single line of numbers
 func (d *Day11) readInput(filename string) ([]int, error) {
-	content, err := os.ReadFile(filename)
+	file, err := parse.ReadFile(filename)
 	if err != nil {
-		return nil, fmt.Errorf("error reading file: %w", err)
+		return nil, err
 	}
 
-	numStrs := strings.Split(string(content), " ")
-	input := make([]int, len(numStrs))
-
-	for i, str := range numStrs {
-		num, err := strconv.Atoi(str)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read number: %s %w", str, err)
-		}
-		input[i] = num
+	sections, err := file.SectionsN(1)
+	if err != nil {
+		return nil, err
+	}
+	if len(sections[0]) != 1 {
+		return nil, sections[0][1].Errorf(0, "stones should be a single line")
 	}
 
-	return input, nil
+	line := sections[0][0]
+	if err := line.Expect(day11Line); err != nil {
+		return nil, err
+	}
+	return line.Ints()
 }
 
 func (d *Day11) part1(input []int) error {
@@ -68,19 +90,58 @@ func (d *Day11) part1(input []int) error {
 }
 
 func (d *Day11) part2(input []int) error {
-	numStones := d.blinkStones(input, 75)
+	count, err := 0, mathx.ErrOverflow
+	if !d.BigInt {
+		count, err = d.blinkStones(input, 75)
+	}
 
-	fmt.Printf("\nTotal Stones: %s\n", solutionStyle.Render(strconv.Itoa(numStones)))
+	numStones := strconv.Itoa(count)
+	var stats memo.Stats
+	switch {
+	case errors.Is(err, mathx.ErrOverflow):
+		if !d.BigInt {
+			fmt.Println(overflowWarning("the number of stones"))
+		}
+		numStones = d.blinkStonesBig(input, 75).String()
+		stats = d.bigStoneCache.Stats()
+	case err != nil:
+		return err
+	default:
+		stats = d.stoneCache.Stats()
+	}
+
+	slog.Info("blink cache", "stats", stats)
+	fmt.Printf("\nCache %s\n", numberStyle.Render(stats.String()))
+	fmt.Printf("\nTotal Stones: %s\n", solutionStyle.Render(numStones))
 	return nil
 }
 
-// blinkStones blinks each stone n times and sums up their counts
-func (d *Day11) blinkStones(stones []int, times int) int {
-	d.stoneCache = make(map[day11CacheEntry]int)
+// blinkStones blinks each stone n times and sums up their counts. It returns mathx.ErrOverflow
+// if there are too many stones for an int.
+func (d *Day11) blinkStones(stones []int, times int) (int, error) {
+	d.stoneCache = memo.New(d.blinkUncached)
+	d.overflowed = false
 	numStones := 0
 
 	for _, stone := range stones {
-		numStones += d.blink(stone, times)
+		var ok bool
+		if numStones, ok = mathx.AddChecked(numStones, d.blink(stone, times)); !ok {
+			d.overflowed = true
+		}
+	}
+	if d.overflowed {
+		return 0, mathx.ErrOverflow
+	}
+	return numStones, nil
+}
+
+// blinkStonesBig is blinkStones with big integer counts
+func (d *Day11) blinkStonesBig(stones []int, times int) *big.Int {
+	d.bigStoneCache = memo.New(d.blinkUncachedBig)
+	numStones := new(big.Int)
+
+	for _, stone := range stones {
+		numStones.Add(numStones, d.bigStoneCache.Get(day11CacheEntry{stone: stone, times: times}))
 	}
 	return numStones
 }
@@ -123,32 +184,48 @@ func (d *Day11) blinkStonesShantz(input []int, times int) int {
 
 // blink blinks this stone a number a times and returns the total number of stones at the end
 func (d *Day11) blink(stone int, times int) int {
-	if times == 0 {
-		return 1
+	if d.stoneCache == nil {
+		d.stoneCache = memo.New(d.blinkUncached)
 	}
+	return d.stoneCache.Get(day11CacheEntry{stone: stone, times: times})
+}
 
-	// if we already counted this stone blink n times, return the cache
-	if count, ok := d.stoneCache[day11CacheEntry{stone: stone, times: times}]; ok {
-		return count
+// blinkUncached runs the rules for a stone and recursively blinks the stones it makes.
+// The recursive blinks go through the cache, so each stone and times is only counted once.
+func (d *Day11) blinkUncached(blink func(day11CacheEntry) int, e day11CacheEntry) int {
+	if e.times == 0 {
+		return 1
 	}
 
 	// run the rules for this new stone
-	stone1, stone2 := d.runRules(stone)
+	stone1, stone2 := d.runRules(e.stone)
 
-	count := 0
+	count := blink(day11CacheEntry{stone: stone1, times: e.times - 1})
 	if stone2 != -1 {
-		// if we made a second stone, recursively blink it
-		// and cache the results
-		numStones := d.blink(stone2, times-1)
-		count += numStones
-		d.stoneCache[day11CacheEntry{stone: stone2, times: times - 1}] = numStones
-	}
-
-	// recursively blink this stone one less time
-	// and cache the results
-	numStones := d.blink(stone1, times-1)
-	d.stoneCache[day11CacheEntry{stone: stone1, times: times - 1}] = numStones
-	count += numStones
+		// if we made a second stone, recursively blink it too
+		var ok bool
+		if count, ok = mathx.AddChecked(count, blink(day11CacheEntry{stone: stone2, times: e.times - 1})); !ok {
+			// too many stones, the count is wrong from here on up so blinkStones has to start over
+			d.overflowed = true
+			return math.MaxInt
+		}
+	}
+	return count
+}
+
+// blinkUncachedBig is blinkUncached with big integer counts. The cached counts are shared, so
+// they are never modified.
+func (d *Day11) blinkUncachedBig(blink func(day11CacheEntry) *big.Int, e day11CacheEntry) *big.Int {
+	if e.times == 0 {
+		return big.NewInt(1)
+	}
+
+	stone1, stone2 := d.runRules(e.stone)
+
+	count := blink(day11CacheEntry{stone: stone1, times: e.times - 1})
+	if stone2 != -1 {
+		count = new(big.Int).Add(count, blink(day11CacheEntry{stone: stone2, times: e.times - 1}))
+	}
 	return count
 }
 
@@ -173,14 +250,10 @@ func (d *Day11) rule1(stone int) (int, bool) {
 
 // rule2 - even numbers are split into two stones
 func (d *Day11) rule2(stone int) (int, int, bool) {
-	digits := int(math.Log10(float64(stone))) + 1
+	digits := mathx.NumDigits(stone)
 	if digits%2 == 0 {
-		// 1234 split is 1234 / 100 = 12
-		//               1234 % 100 = 34
-		k := int(math.Pow10(digits / 2))
-		stone1 := stone / k
-		stone2 := stone % k
-
+		// 1234 splits into 12 and 34
+		stone1, stone2 := mathx.SplitDigits(stone, digits/2)
 		return stone1, stone2, true
 	}
 	return 0, 0, false
@@ -22,9 +22,7 @@ func TestDay11_blink(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			d := &Day11{
-				stoneCache: make(map[day11CacheEntry]int),
-			}
+			d := &Day11{}
 			if got := d.blink(tt.args.stone, tt.args.times); got != tt.want {
 				t.Errorf("Day11.blink() = %v, want %v", got, tt.want)
 			}
@@ -2,13 +2,12 @@ package advent
 
 import (
 	"fmt"
-	"log/slog"
 	"strconv"
 	"strings"
 	"time"
-	"unicode"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/sirgwain/advent-of-code-2024/advent/parse"
 	"github.com/sirgwain/advent-of-code-2024/advent/tui"
 )
 
@@ -19,22 +18,35 @@ type Day12 struct {
 type day12Input [][]rune
 
 type day12Board struct {
-	board  [][]rune
-	width  int
-	height int
-
-	foundEdges [][]uint
-	regions    []day12Region
-	solution   int
-	onStep     func()
+	garden *Grid[rune]
+	// labels holds the index of each plot's region
+	labels  *Grid[int]
+	regions []Component[rune]
+
+	// visited marks the plots the visualization has reached
+	visited *Grid[bool]
+	// found are the regions reached so far, indexed by label. The last one may still be growing.
+	found    []day12Region
+	solution int
+	// the final cost, so we can redact the solution as we get close
+	finalSolution int
+	onStep        func()
 }
 
 type day12Region struct {
+	label    int
 	plotType rune
 	area     int
 	sides    int
 }
 
+// day12Plot is a plot as it's drawn, colored by its region once it's visited
+type day12Plot struct {
+	plotType rune
+	// region is the label of the plot's region, or -1 if it hasn't been visited
+	region int
+}
+
 // Run is the main entry point for a day. It reads the input file and runs the part
 func (d *Day12) Run(part int, filename string, opts ...Option) error {
 	d.Options = newRun(opts...)
@@ -53,6 +65,12 @@ func (d *Day12) Run(part int, filename string, opts ...Option) error {
 	}
 }
 
+// Validate reads the input file and checks its format without solving
+func (d *Day12) Validate(filename string) error {
+	_, err := d.readInput(filename)
+	return err
+}
+
 // RunVisual is like Run, but it starts a bubbletea program and runs the solver in a goroutine
 func (d *Day12) RunVisual(part int, filename string, opts ...Option) error {
 	d.Options = newRun(opts...)
@@ -71,102 +89,97 @@ func (d *Day12) RunVisual(part int, filename string, opts ...Option) error {
 	}
 }
 
+// readInput reads the garden map, one row of plots per line. Every row must be the same width.
 func (d *Day12) readInput(filename string) (day12Input, error) {
-	return readInputAsRunes(filename)
+	input, err := readInputAsRunes(filename)
+	if err != nil {
+		return nil, err
+	}
+	if len(input) == 0 || len(input[0]) == 0 {
+		return nil, fmt.Errorf("%s: no garden plots in the input", filename)
+	}
+	for y, line := range input {
+		if len(line) != len(input[0]) {
+			return nil, &parse.Error{File: filename, Line: y + 1, Text: string(line), Err: fmt.Errorf("row has %d columns, expected %d", len(line), len(input[0]))}
+		}
+	}
+	return input, nil
 }
 
 func (d *Day12) part1(input day12Input) error {
+	regions, err := d.regions(input)
+	if err != nil {
+		return err
+	}
 
 	solution := 0
-
-	board := duplicate2DSlice(input)
-	for y := 0; y < len(board); y++ {
-		for x := 0; x < len(board[y]); x++ {
-			plotType := board[y][x]
-			if unicode.IsLower(plotType) {
-				// we visted this rune already
-				continue
-			}
-			// find all plots this type
-			area, perimeter := d.findRegion(plotType, board, x, y)
-
-			fmt.Printf("Found area for %s, area: %d, perimeter %d\n", string(plotType), area, perimeter)
-			// add the cost to our solution
-			solution += area * perimeter
-		}
+	for _, region := range regions {
+		fmt.Printf("Found area for %s, area: %d, perimeter %d\n", string(region.Value), region.Area, region.Perimeter)
+		// add the cost to our solution
+		solution += region.Area * region.Perimeter
 	}
 
 	fmt.Printf("\nSolution: %s\n", solutionStyle.Render(strconv.Itoa(solution)))
 	return nil
 }
 
-// regions look like this
-// +-+-+-+-+
-// |A A A A|
-// +-+-+-+-+
-// but can also go up/down and form funny shapes
-func (d *Day12) findRegion(plotType rune, board [][]rune, x, y int) (area, perimeter int) {
-	// mark this path as visited
-	visited := unicode.ToLower(plotType)
-	board[y][x] = visited
-
-	area = 1
-	for _, dir := range cardinalDirections {
-		offsetX, offsetY := dir.offsetMultiplier()
-		test := getBoardValue(x+offsetX, y+offsetY, board)
-
-		if test == visited {
-			continue
-		}
-		if test != plotType {
-			// we found an edge
-			perimeter++
-			continue
-		}
+func (d *Day12) part2(input day12Input) error {
+	regions, err := d.regions(input)
+	if err != nil {
+		return err
+	}
 
-		// a neighbor is the same plotType, add its area and perimiter to ours
-		a, p := d.findRegion(plotType, board, x+offsetX, y+offsetY)
-		area += a
-		perimeter += p
+	solution := 0
+	for _, region := range regions {
+		fmt.Printf("Found area for %s, area: %d, sides %d\n", string(region.Value), region.Area, region.Sides)
+		// add the cost to our solution
+		solution += region.Area * region.Sides
 	}
 
-	// clear out this board entry
-	return area, perimeter
+	fmt.Printf("\nSolution: %s\n", solutionStyle.Render(strconv.Itoa(solution)))
+	return nil
 }
 
-func (d *Day12) part2(input day12Input) error {
-	board := day12Board{
-		board:  input,
-		height: len(input),
-		width:  len(input[0]),
+// regions labels the garden's regions, each a connected group of plots of the same type
+func (d *Day12) regions(input day12Input) ([]Component[rune], error) {
+	garden, err := GridFromSlices(input)
+	if err != nil {
+		return nil, err
 	}
-	board.findPlots()
-	fmt.Printf("%s\n%s\n%s", board.view(), board.viewRegions(), board.viewSolution())
-	return nil
+	_, regions := garden.Components()
+	return regions, nil
 }
 
-func (d *Day12) part2Visual(input [][]rune) error {
-	// create a bubbletea program
-	p := tui.NewViewportProgram(tui.NewModel("Day 12 - Part 2").WithMinWidth(100))
-
-	board := day12Board{
-		board:  input,
-		height: len(input),
-		width:  len(input[0]),
+func (d *Day12) part2Visual(input day12Input) error {
+	garden, err := GridFromSlices(input)
+	if err != nil {
+		return err
 	}
+	board := newDay12Board(garden)
+
+	// the gate pauses the solver while we inspect the board
+	gate := tui.NewGate()
+	p := tui.NewViewportProgram(tui.NewModel("Day 12 - Part 2").
+		WithMinWidth(100).
+		WithRenderMode(d.RenderMode).
+		WithInspector(inspector(board.inspect), gate))
+
 	// run the solver in a gouroutine and Send a message to the bubbletea program to update the viewport
 	// on each step
-	width := len(board.board[0])
 	go func() {
+		defer gate.Done()
 		// update the UI
 		board.onStep = func() {
-			content := fmt.Sprintf("%s\n%s\n%s", board.view(), board.viewRegions(), board.viewSolution())
-			p.Send(tui.UpdateViewport(content, width))
+			p.Send(tui.UpdateBoard(board, fmt.Sprintf("%s\n%s", board.viewRegions(), board.viewSolution(d.redaction()))))
+			gate.Step()
 			if d.Options.Delay > 0 {
 				time.Sleep(time.Millisecond * time.Duration(d.Options.Delay))
 			}
 		}
-		board.findPlots()
+		board.findRegions()
+		if d.RedactSolution {
+			p.Send(tui.Reveal(tui.UpdateBoard(board, fmt.Sprintf("%s\n%s", board.viewRegions(), board.viewSolution(noRedaction)))))
+		}
 	}()
 
 	// execute the bubbletea program. This will block until the user pressed q or esc
@@ -176,245 +189,123 @@ func (d *Day12) part2Visual(input [][]rune) error {
 
 	// output the board and final result before exiting the program
 	fmt.Println(board.view())
-	fmt.Println(board.viewSolution())
+	fmt.Println(board.viewSolution(noRedaction))
 	return nil
 }
 
-func (b *day12Board) findPlots() {
-	board := b.board
-	b.foundEdges = make2DSlice(len(board[0]), len(board))
-
-	for y := 0; y < len(board); y++ {
-		for x := 0; x < len(board[y]); x++ {
-			plotType := board[y][x]
-			if unicode.IsLower(plotType) {
-				// we visted this rune already
-				continue
-			}
-			// find all plots this type
-			b.regions = append(b.regions, day12Region{plotType: plotType})
-			region := &b.regions[len(b.regions)-1]
-			area, sides := b.findSidedRegion(region, x, y)
-
-			// fmt.Printf("Found area qfor %s, area: %d, sides %d = %d\n", string(plotType), area, sides, area*sides)
-			// add the cost to our solution
-			b.solution += area * sides
-			if b.onStep != nil {
-				b.onStep()
-			}
-		}
+// newDay12Board labels the garden's regions up front, so the final cost is known before the
+// visualization starts
+func newDay12Board(garden *Grid[rune]) *day12Board {
+	labels, regions := garden.Components()
+	b := &day12Board{
+		garden:  garden,
+		labels:  labels,
+		regions: regions,
+		visited: NewGrid[bool](garden.Size()),
 	}
-
-}
-
-func (b *day12Board) view() string {
-	var sb strings.Builder
-	board := b.board
-	for y := 0; y < len(board); y++ {
-		for x := 0; x < len(board[y]); x++ {
-			if unicode.IsLower(board[y][x]) {
-				// a visited square, start with color "200"
-				color := 200 + int(board[y][x]) - int('a')
-				sb.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color(strconv.Itoa(color))).Render(string(board[y][x])))
-			} else {
-				// a non visted square, start with color "30"
-				color := 30 + int(board[y][x]) - int('a')
-				sb.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color(strconv.Itoa(color))).Render(string(board[y][x])))
-			}
-		}
-		sb.WriteString("\n")
+	for _, region := range regions {
+		b.finalSolution += region.Area * region.Sides
 	}
-	return sb.String()
+	return b
 }
 
-func (b *day12Board) viewRegions() string {
-	var sb strings.Builder
+// findRegions walks each region plot by plot, adding up its area and sides as it goes
+func (b *day12Board) findRegions() {
+	for _, component := range b.regions {
+		b.found = append(b.found, day12Region{label: component.Label, plotType: component.Value})
+		region := &b.found[len(b.found)-1]
+		for _, p := range component.Cells {
+			b.visited.Set(p, true)
+			region.area++
+			// a region has as many sides as corners
+			region.sides += corners(p, b.inRegion(component.Label))
+			b.step()
+		}
 
-	for _, region := range b.regions {
-		color := 200 + int(region.plotType) - int('A')
-		sb.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color(strconv.Itoa(color))).Render(string(region.plotType)))
-		sb.WriteString(fmt.Sprintf(" Area: %s, Sides: %s",
-			numberStyle.Render(strconv.Itoa(region.area)),
-			numberStyle.Render(strconv.Itoa(region.sides)),
-		))
-		sb.WriteString("\n")
+		// add the cost to our solution
+		b.solution += region.area * region.sides
+		b.step()
 	}
-	return sb.String()
-}
-
-func (b *day12Board) viewSolution() string {
-	return fmt.Sprintf("Cost: %s\n", solutionStyle.Render(strconv.Itoa(b.solution)))
 }
 
-// sided regions look like this
-// +-------+
-// |A A A A|
-// +-------+
-// but can also go up/down and form funny shapes
-func (b *day12Board) findSidedRegion(region *day12Region, x, y int) (area, sides int) {
-	plotType := region.plotType
-	// mark this path as visited
-	b.visit(x, y)
-	area += 1
-	region.area += 1
-
-	if b.onStep != nil {
-		b.onStep()
-	}
-
-	// find the sides of this square
-	currentEdges := b.findSides(plotType, x, y)
-	b.foundEdges[y][x] = currentEdges
-
-	if (currentEdges&sideUp) > 0 && b.placeNewEdge(plotType, sideUp, x, y) {
-		sides++
-		region.sides++
-		slog.Debug(fmt.Sprintf("%s %d,%d added side: up", string(plotType), x, y))
-	}
-	if (currentEdges&sideRight) > 0 && b.placeNewEdge(plotType, sideRight, x, y) {
-		sides++
-		region.sides++
-		slog.Debug(fmt.Sprintf("%s %d,%d added side: right", string(plotType), x, y))
-	}
-	if (currentEdges&sideDown) > 0 && b.placeNewEdge(plotType, sideDown, x, y) {
-		sides++
-		region.sides++
-		slog.Debug(fmt.Sprintf("%s %d,%d added side: down", string(plotType), x, y))
-	}
-	if (currentEdges&sideLeft) > 0 && b.placeNewEdge(plotType, sideLeft, x, y) {
-		sides++
-		region.sides++
-		slog.Debug(fmt.Sprintf("%s %d,%d added side: left", string(plotType), x, y))
-	}
-
+func (b *day12Board) step() {
 	if b.onStep != nil {
 		b.onStep()
 	}
-	slog.Debug(fmt.Sprintf("%s area %d, sides: %d", string(plotType), region.area, region.sides))
-
-	// now move to any like squares, sending our sides wih it
-	for _, dir := range cardinalDirections {
-		offsetX, offsetY := dir.offsetMultiplier()
-
-		if b.visited(x+offsetX, y+offsetY) || !b.samePlotType(plotType, x+offsetX, y+offsetY) {
-			continue
-		}
-
-		// a neighbor is the same plotType, add its area and perimiter to ours
-		a, s := b.findSidedRegion(region, x+offsetX, y+offsetY)
-		area += a
-		sides += s
-	}
-
-	// clear out this board entry
-	return area, sides
 }
 
-func (b *day12Board) placeNewEdge(plotType rune, side uint, x, y int) bool {
-	currentEdges := getBoardValue(x, y, b.foundEdges)
-	offsetX, offsetY := 0, 0
-	switch side {
-	// up/down side checks need to look left and right for already found edges
-	case sideUp:
-		fallthrough
-	case sideDown:
-		offsetX = 1
-	case sideLeft:
-		fallthrough
-	case sideRight:
-		offsetY = 1
+// inRegion returns a func that reports whether a position is in the region with this label
+func (b *day12Board) inRegion(label int) func(p position) bool {
+	return func(p position) bool {
+		l, ok := b.labels.Lookup(p)
+		return ok && l == label
 	}
+}
 
-	if (currentEdges & side) == 0 {
-		return false
+// plot returns the plot at p as it should be drawn
+func (b *day12Board) plot(p position) day12Plot {
+	plot := day12Plot{plotType: b.garden.Get(p), region: -1}
+	if b.visited.Get(p) {
+		plot.region = b.labels.Get(p)
 	}
+	return plot
+}
 
-	// if any neighbor in this line has placed an edge, don't place one
-	count := 1
-	for {
-		pos := position{x + (offsetX * count), y + (offsetY * count)}
-		// stop checking if we hit a board position that is different from us
-		if !validPosition(pos, b.width, b.height) || !b.samePlotType(plotType, pos.x, pos.y) {
-			break
-		}
-		if (getBoardValue(pos.x, pos.y, b.foundEdges) & side) > 0 {
-			return false
-		}
-		// don't continue searching neighbors if we've visited this one
-		// we only skip over unvisited spaces
-		if b.visited(pos.x, pos.y) {
-			break
-		}
-		if b.findSides(plotType, pos.x, pos.y)&side == 0 {
-			break
-		}
-		count++
+// plotColor returns the color of a plot, visited plots are colored by their region
+// and unvisited plots are muted
+func plotColor(plot day12Plot) lipgloss.TerminalColor {
+	if plot.region >= 0 {
+		return theme.CategoryColor(plot.region)
 	}
+	return theme.Muted
+}
 
-	// check neighbors in the other direction
-	count = 1
-	for {
-		pos := position{x - (offsetX * count), y - (offsetY * count)}
-		// stop checking if we hit a board position that is different from us
-		if !validPosition(pos, b.width, b.height) || !b.samePlotType(plotType, pos.x, pos.y) {
-			break
-		}
-		if (getBoardValue(pos.x, pos.y, b.foundEdges) & side) > 0 {
-			return false
-		}
-		// don't continue searching neighbors if we've visited this one
-		// we only skip over unvisited spaces
-		if b.visited(pos.x, pos.y) {
-			break
-		}
-		// vc
-		// CC
-		// Qc ^ checking up, we have a left side and another left side up a ways on the board, but the side is broken by unvisited squares
-		// if the line is broken, don't continue
-		if b.findSides(plotType, pos.x, pos.y)&side == 0 {
-			break
-		}
-		count++
-	}
-	return true
+func (b *day12Board) view() string {
+	return b.garden.Render(func(p position, v rune) string {
+		return day12Renderer.RenderValue(b.plot(p))
+	})
 }
 
-func (b *day12Board) samePlotType(plotType rune, x, y int) bool {
-	return unicode.ToUpper(getBoardValue(x, y, b.board)) == plotType
+// Size and Cell make the board a tui.Board
+func (b *day12Board) Size() (width, height int) {
+	return b.garden.Size()
 }
 
-func (b *day12Board) visit(x, y int) {
-	b.board[y][x] = unicode.ToLower(b.board[y][x])
+func (b *day12Board) Cell(x, y int) tui.Cell {
+	p := position{X: x, Y: y}
+	return day12Renderer.Cell(p, b.plot(p))
 }
 
-func (b *day12Board) visited(x, y int) bool {
-	return unicode.IsLower(getBoardValue(x, y, b.board))
+// inspect describes a plot and the region it belongs to
+func (b *day12Board) inspect(p position) string {
+	var sb strings.Builder
+	visited := b.visited.Get(p)
+	fmt.Fprintf(&sb, "plot: %s\nvisited: %t\n", string(b.garden.Get(p)), visited)
+	if !visited {
+		return sb.String()
+	}
+
+	label := b.labels.Get(p)
+	region := b.found[label]
+	fmt.Fprintf(&sb, "corners: %d\n", corners(p, b.inRegion(label)))
+	fmt.Fprintf(&sb, "\nregion: %d\narea: %d\nsides: %d\n", label, region.area, region.sides)
+	return sb.String()
 }
 
-func (b *day12Board) findSides(plotType rune, x, y int) uint {
-	var sidesFound uint
-	for _, dir := range cardinalDirections {
-		offsetX, offsetY := dir.offsetMultiplier()
-		test := getBoardValue(x+offsetX, y+offsetY, b.board)
+func (b *day12Board) viewRegions() string {
+	var sb strings.Builder
 
-		if test == unicode.ToLower(plotType) {
-			continue
-		}
-		if test != plotType {
-			// only add sides if we haven't found it yet
-			if dir == directionUp {
-				sidesFound |= sideUp
-			}
-			if dir == directionRight {
-				sidesFound |= sideRight
-			}
-			if dir == directionDown {
-				sidesFound |= sideDown
-			}
-			if dir == directionLeft {
-				sidesFound |= sideLeft
-			}
-		}
+	for _, region := range b.found {
+		sb.WriteString(fg(theme.CategoryColor(region.label)).Render(string(region.plotType)))
+		sb.WriteString(fmt.Sprintf(" Area: %s, Sides: %s",
+			numberStyle.Render(strconv.Itoa(region.area)),
+			numberStyle.Render(strconv.Itoa(region.sides)),
+		))
+		sb.WriteString("\n")
 	}
-	return sidesFound
+	return sb.String()
+}
+
+func (b *day12Board) viewSolution(redact redaction) string {
+	return fmt.Sprintf("Cost: %s\n", redact.render(b.solution, b.finalSolution))
 }
@@ -0,0 +1,113 @@
+package advent
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirgwain/advent-of-code-2024/advent/parse"
+)
+
+const day12Example = `RRRRIICCFF
+RRRRIICCCF
+VVRRRCCFFF
+VVRCCCJFFF
+VVVVCJJCFE
+VVIVCCJJEE
+VVIIICJJEE
+MIIIIIJJEE
+MIIISIJEEE
+MMMISSJEEE`
+
+func TestDay12_regions(t *testing.T) {
+	garden, err := ParseGrid(strings.NewReader(day12Example), parseRune)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := &Day12{}
+	regions, err := d.regions(garden.Slices())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fenceCost, bulkCost := 0, 0
+	for _, region := range regions {
+		fenceCost += region.Area * region.Perimeter
+		bulkCost += region.Area * region.Sides
+	}
+	if fenceCost != 1930 {
+		t.Errorf("fence cost = %d, want 1930", fenceCost)
+	}
+	if bulkCost != 1206 {
+		t.Errorf("bulk cost = %d, want 1206", bulkCost)
+	}
+
+	// the visualization's solver should agree, without the regions changing the garden
+	board := newDay12Board(garden)
+	board.findRegions()
+	if board.solution != bulkCost || board.finalSolution != bulkCost {
+		t.Errorf("findRegions() solution = %d, final %d, regions solution = %d", board.solution, board.finalSolution, bulkCost)
+	}
+	if got := garden.String(); got != day12Example+"\n" {
+		t.Errorf("garden changed after finding regions\n%s", got)
+	}
+}
+
+func TestDay12_findRegionsAnyRune(t *testing.T) {
+	// plots don't have to be letters
+	garden, err := ParseGrid(strings.NewReader("11.\n1..\n#.é"), parseRune)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	board := newDay12Board(garden)
+	board.findRegions()
+	// 1: area 3, 6 sides, .: area 4, 8 sides, # and é: area 1, 4 sides
+	if want := 3*6 + 4*8 + 4 + 4; board.solution != want {
+		t.Errorf("findRegions() solution = %d, want %d", board.solution, want)
+	}
+	if len(board.found) != 4 {
+		t.Errorf("findRegions() found %d regions, want 4", len(board.found))
+	}
+	for p := range garden.All() {
+		if !board.visited.Get(p) {
+			t.Errorf("plot %v wasn't visited", p)
+		}
+	}
+}
+
+func TestDay12_readInput(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+		line    int
+	}{
+		{"example", day12Example, false, 0},
+		{"empty", "", true, 0},
+		{"blank lines", "\n\n", true, 0},
+		{"ragged row", "AAA\nA\nAAA", true, 2},
+		{"blank line between rows", "AAA\n\nAAA", true, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "day12.txt")
+			if err := os.WriteFile(filename, []byte(tt.input), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			d := &Day12{}
+			_, err := d.readInput(filename)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Day12.readInput() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var parseErr *parse.Error
+			if tt.line > 0 && (!errors.As(err, &parseErr) || parseErr.Line != tt.line || parseErr.File != filename) {
+				t.Errorf("Day12.readInput() error = %v, want a parse.Error on line %d", err, tt.line)
+			}
+		})
+	}
+}
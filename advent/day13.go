@@ -1,11 +1,12 @@
 package advent
 
 import (
-	"bufio"
+	"errors"
 	"fmt"
-	"os"
-	"regexp"
-	"strconv"
+	"math/big"
+
+	"github.com/sirgwain/advent-of-code-2024/advent/mathx"
+	"github.com/sirgwain/advent-of-code-2024/advent/parse"
 )
 
 type Day13 struct {
@@ -45,9 +46,9 @@ func (s day13Solution) empty() bool {
 func (m day13Machine) press(button button, pos position) position {
 
 	if button == buttonA {
-		return position{pos.x + m.buttonA.x, pos.y + m.buttonA.y}
+		return pos.Add(m.buttonA)
 	}
-	return position{pos.x + m.buttonB.x, pos.y + m.buttonB.y}
+	return pos.Add(m.buttonB)
 }
 
 // Run is the main entry point for a day. It reads the input file and runs the part
@@ -68,83 +69,131 @@ func (d *Day13) Run(part int, filename string, opts ...Option) error {
 	}
 }
 
+// Validate reads the input file and checks its format without solving
+func (d *Day13) Validate(filename string) error {
+	_, err := d.readInput(filename)
+	return err
+}
+
+// day13Vector is an X, Y pair from a button or prize line
+type day13Vector struct {
+	X int `parse:"x"`
+	Y int `parse:"y"`
+}
+
+func (v day13Vector) position() position {
+	return position{X: v.X, Y: v.Y}
+}
+
+// readInput reads a section of three lines for each machine
+//
+//	Button A: X+94, Y+34
+//	Button B: X+22, Y+67
+//	Prize: X=8400, Y=5400
 func (d *Day13) readInput(filename string) (day13Input, error) {
-	file, err := os.Open(filename)
+	file, err := parse.ReadFile(filename)
 	if err != nil {
-		return nil, fmt.Errorf("error opening file: %w", err)
+		return nil, err
 	}
-	defer file.Close()
 
-	buttonPattern := `Button [AB]: X\+(\d+), Y\+(\d+)`
-	reButton, err := regexp.Compile(buttonPattern)
+	buttonA, err := parse.NewMatcher[day13Vector](`^Button A: X\+(?P<x>\d+), Y\+(?P<y>\d+)$`)
 	if err != nil {
-		return nil, fmt.Errorf("bad regex %s %w", buttonPattern, err)
+		return nil, err
 	}
-
-	prizePattern := `Prize: X=(\d+), Y=(\d+)`
-	rePrize, err := regexp.Compile(prizePattern)
+	buttonB, err := parse.NewMatcher[day13Vector](`^Button B: X\+(?P<x>\d+), Y\+(?P<y>\d+)$`)
+	if err != nil {
+		return nil, err
+	}
+	prize, err := parse.NewMatcher[day13Vector](`^Prize: X=(?P<x>\d+), Y=(?P<y>\d+)$`)
 	if err != nil {
-		return nil, fmt.Errorf("bad regex %s %w", prizePattern, err)
+		return nil, err
 	}
+	matchers := []*parse.Matcher[day13Vector]{buttonA, buttonB, prize}
 
 	var input []day13Machine
+	for _, section := range file.Sections() {
+		if len(section) != len(matchers) {
+			return nil, section[0].Errorf(0, "expected %d lines for a machine, found %d", len(matchers), len(section))
+		}
 
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		var machine day13Machine
+		var vectors [3]day13Vector
+		for i, line := range section {
+			if vectors[i], err = matchers[i].Match(line); err != nil {
+				return nil, err
+			}
+		}
 
-		// Button A: X+94, Y+34
-		line := scanner.Bytes()
-		match := reButton.FindSubmatch(line)
-		machine.buttonA = position{mustAtoi(match[1]), mustAtoi(match[2])}
+		input = append(input, day13Machine{
+			buttonA: vectors[0].position(),
+			buttonB: vectors[1].position(),
+			prize:   vectors[2].position(),
+		})
+	}
 
-		// Button B: X+22, Y+67
-		scanner.Scan()
-		line = scanner.Bytes()
-		match = reButton.FindSubmatch(line)
-		machine.buttonB = position{mustAtoi(match[1]), mustAtoi(match[2])}
+	return input, nil
+}
 
-		scanner.Scan()
-		line = scanner.Bytes()
-		match = rePrize.FindSubmatch(line)
-		machine.prize = position{mustAtoi(match[1]), mustAtoi(match[2])}
-		scanner.Scan()
+func (d *Day13) part1(input day13Input) error {
+	return d.solveMachines(input, 0, 100)
+}
 
-		input = append(input, machine)
-	}
+func (d *Day13) part2(input day13Input) error {
+	return d.solveMachines(input, 10000000000000, 0)
+}
 
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("error reading file: %w", err)
+// solveMachines finds the cheapest way to win each prize and adds up the tokens. If a machine's
+// math overflows an int, or the BigInt option is set, it's solved with big integers instead.
+func (d *Day13) solveMachines(input day13Input, prizeOffset int, buttonLimit int) error {
+	var solution mathx.Sum
+	if d.BigInt {
+		solution.UseBig()
 	}
 
-	return input, nil
-}
+	for i, machine := range input {
+		fmt.Printf("Machine %d\n", i+1)
 
-func (d *Day13) part1(input day13Input) error {
+		presses, err := day13Solution{}, mathx.ErrOverflow
+		if !d.BigInt {
+			presses, err = machine.findBestSolutionWithAlgrebra(prizeOffset, buttonLimit)
+		}
 
-	solution := 0
+		// the presses as big ints, nil if there is no solution
+		var a, b *big.Int
+		switch {
+		case errors.Is(err, mathx.ErrOverflow):
+			if !d.BigInt {
+				fmt.Println(overflowWarning(fmt.Sprintf("machine %d", i+1)))
+			}
+			a, b, _ = machine.findBestSolutionBig(prizeOffset, buttonLimit)
+		case err != nil:
+			return err
+		case !presses.empty():
+			a, b = big.NewInt(int64(presses.a)), big.NewInt(int64(presses.b))
+		}
 
-	for _, machine := range input {
-		// presses := machine.findBestSolution()
-		presses := machine.findBestSolutionWithAlgrebra(0, 100)
-		if presses.empty() {
+		if a == nil {
 			// no solution
-			fmt.Printf("Prize: X=%d, Y=%d\n", machine.prize.x, machine.prize.y)
-			fmt.Printf("No solution\n")
+			fmt.Printf("Prize: X=%d, Y=%d\n", machine.prize.X, machine.prize.Y)
+			fmt.Printf("No solution\n\n")
 			continue
 		}
-		fmt.Printf("Button A: X+%d, Y+%d\n", machine.buttonA.x, machine.buttonA.y)
-		fmt.Printf("Button B: X+%d, Y+%d\n", machine.buttonB.x, machine.buttonB.y)
-		fmt.Printf("Prize: X=%d, Y=%d\n", machine.prize.x, machine.prize.y)
+
+		tokens := new(big.Int).Add(new(big.Int).Mul(a, big.NewInt(3)), b)
+		fmt.Printf("Button A: X+%d, Y+%d\n", machine.buttonA.X, machine.buttonA.Y)
+		fmt.Printf("Button B: X+%d, Y+%d\n", machine.buttonB.X, machine.buttonB.Y)
+		fmt.Printf("Prize: X=%d+%d, Y=%d+%d\n", machine.prize.X, prizeOffset, machine.prize.Y, prizeOffset)
 		fmt.Printf("\nBest Presses: A: %s, B: %s => %s tokens\n\n",
-			solutionStyle.Render(strconv.Itoa(presses.a)),
-			solutionStyle.Render(strconv.Itoa(presses.b)),
-			solutionStyle.Render(strconv.Itoa(presses.tokens())),
+			solutionStyle.Render(a.String()),
+			solutionStyle.Render(b.String()),
+			solutionStyle.Render(tokens.String()),
 		)
-		solution += presses.tokens()
+		solution.AddBig(tokens)
 	}
 
-	fmt.Printf("\nSolution: %s\n", solutionStyle.Render(strconv.Itoa(solution)))
+	if solution.IsBig() && !d.BigInt {
+		fmt.Println(overflowWarning("the total"))
+	}
+	fmt.Printf("\nSolution: %s\n", solutionStyle.Render(solution.String()))
 	return nil
 }
 
@@ -157,35 +206,49 @@ func (d *Day13) part1(input day13Input) error {
 // 24a + 85b = 6844 = ax*a + bx*b = px
 // 90a + 62b = 6152 = ay*a + by*b = py
 // or in matrix land AX=B
-func (m *day13Machine) findBestSolutionWithAlgrebra(prizeOffset int, buttonLimit int) day13Solution {
+func (m *day13Machine) findBestSolutionWithAlgrebra(prizeOffset int, buttonLimit int) (day13Solution, error) {
 
 	// Coefficients of the equations
-	ax, bx, px := m.buttonA.x, m.buttonB.x, m.prize.x+prizeOffset
-	ay, by, py := m.buttonA.y, m.buttonB.y, m.prize.y+prizeOffset
-
-	// Determinant of A
-	det := ax*by - ay*bx
-	if det == 0 {
-		// no solution
-		return day13Solution{}
+	px, okX := mathx.AddChecked(m.prize.X, prizeOffset)
+	py, okY := mathx.AddChecked(m.prize.Y, prizeOffset)
+	if !okX || !okY {
+		return day13Solution{}, mathx.ErrOverflow
 	}
+	ax, bx := m.buttonA.X, m.buttonB.X
+	ay, by := m.buttonA.Y, m.buttonB.Y
 
-	// Inverse of A multiplied by B
-	aPresses := (px*by - py*bx) / det
-	bPresses := (ax*py - ay*px) / det
+	aPresses, bPresses, ok, err := mathx.Solve2Checked(ax, bx, ay, by, px, py)
+	if err != nil {
+		return day13Solution{}, err
+	}
+	// the solution is exact, but buttons can't be pressed a negative number of times, or more than the limit
+	if !ok || aPresses < 0 || bPresses < 0 || (buttonLimit > 0 && (aPresses > buttonLimit || bPresses > buttonLimit)) {
+		// no solution in whole button presses
+		return day13Solution{}, nil
+	}
 
-	// check the answer
-	arrivedX := m.buttonA.x*aPresses + m.buttonB.x*bPresses
-	arrivedY := m.buttonA.y*aPresses + m.buttonB.y*bPresses
+	fmt.Printf("Pressing button A %d times moves to (%d,%d)\n", aPresses, m.buttonA.X*aPresses, m.buttonA.Y*aPresses)
+	fmt.Printf("Pressing button B %d times moves to (%d,%d)\n", bPresses, m.buttonB.X*bPresses, m.buttonB.Y*bPresses)
+	fmt.Printf("Arriving at prize (%d,%d)\n", px, py)
+	return day13Solution{a: aPresses, b: bPresses}, nil
+}
 
-	if arrivedX != m.prize.x+prizeOffset || arrivedY != m.prize.y+prizeOffset {
-		return day13Solution{}
+// findBestSolutionBig is findBestSolutionWithAlgrebra with big integers, for prizes too far away for an int
+func (m *day13Machine) findBestSolutionBig(prizeOffset int, buttonLimit int) (a, b *big.Int, ok bool) {
+	offset := big.NewInt(int64(prizeOffset))
+	px := new(big.Int).Add(big.NewInt(int64(m.prize.X)), offset)
+	py := new(big.Int).Add(big.NewInt(int64(m.prize.Y)), offset)
+	ax, bx := big.NewInt(int64(m.buttonA.X)), big.NewInt(int64(m.buttonB.X))
+	ay, by := big.NewInt(int64(m.buttonA.Y)), big.NewInt(int64(m.buttonB.Y))
+
+	a, b, ok = mathx.Solve2Big(ax, bx, ay, by, px, py)
+	if !ok || a.Sign() < 0 || b.Sign() < 0 {
+		return nil, nil, false
 	}
-
-	fmt.Printf("Pressing button A %d times moves to (%d,%d)\n", aPresses, m.buttonA.x*aPresses, m.buttonA.y*aPresses)
-	fmt.Printf("Pressing button B %d times moves to (%d,%d)\n", bPresses, m.buttonB.x*bPresses, m.buttonB.y*bPresses)
-	fmt.Printf("Arriving at (%d,%d) prize is at (%d,%d)\n", arrivedX, arrivedY, m.prize.x+prizeOffset, m.prize.y+prizeOffset)
-	return day13Solution{a: aPresses, b: bPresses}
+	if limit := big.NewInt(int64(buttonLimit)); buttonLimit > 0 && (a.Cmp(limit) > 0 || b.Cmp(limit) > 0) {
+		return nil, nil, false
+	}
+	return a, b, true
 }
 
 func (m *day13Machine) findBestSolution() day13Solution {
@@ -202,7 +265,7 @@ func (m *day13Machine) findSolutionFrom(pos position, presses, best day13Solutio
 
 	m.tried[presses] = true
 	if pos == m.prize {
-		fmt.Printf("Solution: A: %d, B: %d => %d,%d\n", presses.a, presses.b, pos.x, pos.y)
+		fmt.Printf("Solution: A: %d, B: %d => %v\n", presses.a, presses.b, pos)
 		// found solution
 		if presses.tokens() < best.tokens() || best.empty() {
 			best = presses
@@ -230,34 +293,3 @@ func (m *day13Machine) findSolutionFrom(pos position, presses, best day13Solutio
 
 	return bestB
 }
-
-func (d *Day13) part2(input []day13Machine) error {
-	solution := 0
-
-	prizeOffset := 10000000000000
-	for i, machine := range input {
-		fmt.Printf("Machine %d\n", i+1)
-		presses := machine.findBestSolutionWithAlgrebra(prizeOffset, 0)
-		if presses.empty() {
-			// no solution
-			fmt.Printf("Prize: X=%d, Y=%d\n", machine.prize.x+prizeOffset, machine.prize.y+prizeOffset)
-			fmt.Printf("No solution\n")
-			fmt.Println()
-			continue
-		}
-
-		fmt.Printf("Button A: X+%d, Y+%d\n", machine.buttonA.x, machine.buttonA.y)
-		fmt.Printf("Button B: X+%d, Y+%d\n", machine.buttonB.x, machine.buttonB.y)
-		fmt.Printf("Prize: X=%d, Y=%d\n", machine.prize.x+prizeOffset, machine.prize.y+prizeOffset)
-		fmt.Printf("\nBest Presses: A: %s, B: %s => %s tokens\n\n",
-			solutionStyle.Render(strconv.Itoa(presses.a)),
-			solutionStyle.Render(strconv.Itoa(presses.b)),
-			solutionStyle.Render(strconv.Itoa(presses.tokens())),
-		)
-		solution += presses.tokens()
-		fmt.Println()
-	}
-
-	fmt.Printf("\nSolution: %s\n", solutionStyle.Render(strconv.Itoa(solution)))
-	return nil
-}
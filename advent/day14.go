@@ -1,16 +1,14 @@
 package advent
 
 import (
-	"bufio"
 	"fmt"
 	"log/slog"
-	"os"
-	"regexp"
+	"math"
 	"strconv"
 	"strings"
 
-	"github.com/charmbracelet/lipgloss"
-	"github.com/sirgwain/advent-of-code-2024/advent/color"
+	"github.com/sirgwain/advent-of-code-2024/advent/mathx"
+	"github.com/sirgwain/advent-of-code-2024/advent/parse"
 	"github.com/sirgwain/advent-of-code-2024/advent/tui"
 )
 
@@ -38,10 +36,6 @@ type day14Board struct {
 
 const robotChar = '☹'
 
-var robotRedRender = robotGreenStyle.Render(string(robotChar))
-var robotGreenRender = robotRedStyle.Render(string(robotChar))
-var midRender = lipgloss.NewStyle().Background(color.DarkBlue).Render(".")
-
 // Run is the main entry point for a day. It reads the input file and runs the part
 func (d *Day14) Run(part int, filename string, opts ...Option) error {
 	d.Options = newRun(opts...)
@@ -60,44 +54,66 @@ func (d *Day14) Run(part int, filename string, opts ...Option) error {
 	}
 }
 
+// Validate reads the input file and checks its format without solving
+func (d *Day14) Validate(filename string) error {
+	_, err := d.readInput(filename)
+	return err
+}
+
+// day14Header is the board size line at the top of the input, like w=101,h=103
+type day14Header struct {
+	Width  int `parse:"w"`
+	Height int `parse:"h"`
+}
+
+// day14Line is a robot line, like p=0,4 v=3,-3
+type day14Line struct {
+	X  int `parse:"x"`
+	Y  int `parse:"y"`
+	VX int `parse:"vx"`
+	VY int `parse:"vy"`
+}
+
 func (d *Day14) readInput(filename string) (day14Input, error) {
-	file, err := os.Open(filename)
+	file, err := parse.ReadFile(filename)
+	if err != nil {
+		return day14Input{}, err
+	}
+	if len(file.Lines) == 0 {
+		return day14Input{}, fmt.Errorf("%s: no input", filename)
+	}
+
+	reHeader, err := parse.NewMatcher[day14Header](`^w=(?P<w>\d+),h=(?P<h>\d+)$`)
 	if err != nil {
-		return day14Input{}, fmt.Errorf("error opening file: %w", err)
+		return day14Input{}, err
 	}
-	defer file.Close()
 
-	boardPattern := `w=(\d+),h=(\d+)`
-	reBoard, err := regexp.Compile(boardPattern)
+	reRobot, err := parse.NewMatcher[day14Line](`^p=(?P<x>\d+),(?P<y>\d+) v=(?P<vx>-?\d+),(?P<vy>-?\d+)$`)
 	if err != nil {
-		return day14Input{}, fmt.Errorf("bad regex %s %w", boardPattern, err)
+		return day14Input{}, err
 	}
 
-	robotPattern := `p=(\d+),(\d+) v=([-\d]+),([-\d]+)`
-	reRobot, err := regexp.Compile(robotPattern)
+	header, err := reHeader.Match(file.Lines[0])
 	if err != nil {
-		return day14Input{}, fmt.Errorf("bad regex %s %w", robotPattern, err)
+		return day14Input{}, err
 	}
+	input := day14Input{width: header.Width, height: header.Height}
 
-	var input day14Input
-
-	i := 0
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := scanner.Bytes()
-		if i == 0 {
-			match := reBoard.FindSubmatch(line)
-			input.width = mustAtoi(match[1])
-			input.height = mustAtoi(match[2])
-			i++
+	for _, line := range file.Lines[1:] {
+		if strings.TrimSpace(line.Text) == "" {
 			continue
 		}
-		match := reRobot.FindSubmatch(line)
-		r := day14robot{
-			position: position{mustAtoi(match[1]), mustAtoi(match[2])},
-			velocity: position{mustAtoi(match[3]), mustAtoi(match[4])},
+		robot, err := reRobot.Match(line)
+		if err != nil {
+			return day14Input{}, err
 		}
-		input.robots = append(input.robots, r)
+		if robot.X >= input.width || robot.Y >= input.height {
+			return day14Input{}, line.Errorf(0, "robot is outside the %dx%d board", input.width, input.height)
+		}
+		input.robots = append(input.robots, day14robot{
+			position: position{X: robot.X, Y: robot.Y},
+			velocity: position{X: robot.VX, Y: robot.VY},
+		})
 	}
 	return input, nil
 }
@@ -123,7 +139,7 @@ func (d *Day14) RunVisual(part int, filename string, opts ...Option) error {
 func (d *Day14) part1(input day14Input) error {
 	solution := 0
 	board := input
-	board.midLowerRight = position{board.width - 1, board.height - 1}
+	board.midLowerRight = position{X: board.width - 1, Y: board.height - 1}
 	for range 100 {
 		// fmt.Printf("%s\n\n", input.view())
 		board.move(1)
@@ -140,43 +156,52 @@ func (d *Day14) part2(input day14Input) error {
 	board.midUpperLeft, board.midLowerRight = board.treeArea()
 	fmt.Printf("%s\n", board.view())
 
-	for {
-		board.move(1)
-		board.seconds++
-		if board.tree() > .5 {
-			board.confidence = int(board.tree() * 100)
-			break
-		}
+	seconds, err := board.treeSeconds()
+	if err != nil {
+		return err
 	}
+	board.move(seconds)
+	board.seconds = seconds
+	board.confidence = int(board.tree() * 100)
+
 	fmt.Println(board.view())
-	fmt.Println(board.viewSolution())
+	fmt.Println(board.viewSolution(noRedaction))
 
 	return nil
 }
 
 func (d *Day14) part2Visual(input day14Input) error {
 	// create a bubbletea program
-	p := tui.NewViewportProgram(tui.NewModel("Day 14"))
+	p := tui.NewViewportProgram(tui.NewModel("Day 14").WithRenderMode(d.RenderMode))
 
 	board := input
 	board.midUpperLeft, board.midLowerRight = board.treeArea()
 
+	// we know when the tree shows up before we start, so the solution can be redacted like any other
+	solution, err := board.treeSeconds()
+	if err != nil {
+		return err
+	}
+
 	// run the solver in a gouroutine and Send a message to the bubbletea program to update the viewport
 	// on each step
 	go func() {
-		view := board.view()
+		cells := board.cells()
 		seconds := 0
-		for {
+		for seconds < solution {
 			board.move(1)
 			seconds++
-			treeConfidence := board.tree()
-			if treeConfidence > .5 {
-				view = board.view()
-				board.seconds = seconds
-				board.confidence = int(treeConfidence * 100)
-			}
-			content := fmt.Sprintf("%s\n%s - current: %d (%d)", view, board.viewSolution(), seconds, int(treeConfidence*100))
-			p.Send(tui.UpdateViewport(content, board.width))
+			cells = board.cells()
+			footer := fmt.Sprintf("seconds: %s (%d)", d.redaction().render(seconds, solution), int(board.tree()*100))
+			p.Send(tui.UpdateBoard(cells, footer))
+		}
+		board.seconds = seconds
+		board.confidence = int(board.tree() * 100)
+
+		// stop at the tree
+		p.Send(tui.UpdateBoard(cells, board.viewSolution(d.redaction())))
+		if d.RedactSolution {
+			p.Send(tui.Reveal(tui.UpdateBoard(cells, board.viewSolution(noRedaction))))
 		}
 	}()
 
@@ -187,42 +212,97 @@ func (d *Day14) part2Visual(input day14Input) error {
 
 	// output the board and final result before exiting the program
 	fmt.Println(board.view())
-	fmt.Println(board.viewSolution())
+	fmt.Println(board.viewSolution(noRedaction))
 	return nil
 }
 
-func (b day14Board) view() string {
-	robots := make(map[position]int, len(b.robots))
+// robotCounts returns the number of robots at each occupied position
+func (b day14Board) robotCounts() *SparseGrid[int] {
+	robots := NewSparseGrid[int]()
 	for _, r := range b.robots {
-		robots[r.position]++
+		robots.Update(r.position, func(count int) int { return count + 1 })
 	}
-	var sb strings.Builder
+	return robots
+}
+
+// treeBoundary returns true if this position is on the boundary we think the tree might be in
+func (b day14Board) treeBoundary(x, y int) bool {
+	return ((x == b.midUpperLeft.X || x == b.midLowerRight.X) && y > b.midUpperLeft.Y && y < b.midLowerRight.Y) ||
+		((y == b.midUpperLeft.Y || y == b.midLowerRight.Y) && x > b.midUpperLeft.X && x < b.midLowerRight.X)
+}
+
+func (b day14Board) view() string {
+	robots := b.robotCounts()
+	return robots.RenderRect(position{}, position{X: b.width - 1, Y: b.height - 1}, func(p position, count int, ok bool) string {
+		switch {
+		case ok && count > 1:
+			return robotStackedRender
+		case ok:
+			return robotSingleRender
+		case b.treeBoundary(p.X, p.Y):
+			return midRender
+		}
+		return "."
+	})
+}
+
+// cells renders the board as tui cells so the viewport can compact it for large boards
+func (b day14Board) cells() *tui.Cells {
+	robots := b.robotCounts()
+	cells := tui.NewCells(b.width, b.height)
 
 	for y := 0; y < b.height; y++ {
 		for x := 0; x < b.width; x++ {
-			if count, ok := robots[position{x, y}]; ok {
+			if count, ok := robots.Lookup(position{X: x, Y: y}); ok {
 				if count > 1 {
-					sb.WriteString(robotRedRender)
+					cells.Set(x, y, tui.Cell{Char: robotChar, Fg: robotStackedStyle.GetForeground()})
 				} else {
-					sb.WriteString(robotGreenRender)
+					cells.Set(x, y, tui.Cell{Char: robotChar, Fg: robotSingleStyle.GetForeground()})
 				}
+			} else if b.treeBoundary(x, y) {
+				cells.Set(x, y, tui.Cell{Char: '.', Bg: midStyle.GetBackground()})
 			} else {
-				// draw the boundary we think the tree might be in
-				if ((x == b.midUpperLeft.x || x == b.midLowerRight.x) && y > b.midUpperLeft.y && y < b.midLowerRight.y) ||
-					((y == b.midUpperLeft.y || y == b.midLowerRight.y) && x > b.midUpperLeft.x && x < b.midLowerRight.x) {
-					sb.WriteString(midRender)
-				} else {
-					sb.WriteRune('.')
-				}
+				cells.Set(x, y, tui.Cell{Char: '.'})
 			}
 		}
-		sb.WriteString("\n")
 	}
 
-	return sb.String()
+	return cells
+}
+func (b day14Board) viewSolution(redact redaction) string {
+	return fmt.Sprintf("\nseconds: %s - %s%%", redact.render(b.seconds, b.seconds), solutionStyle.Render(strconv.Itoa(b.confidence)))
 }
-func (b day14Board) viewSolution() string {
-	return fmt.Sprintf("\nseconds: %s - %s%%", solutionStyle.Render(strconv.Itoa(b.seconds)), solutionStyle.Render(strconv.Itoa(b.confidence)))
+
+// treeSeconds finds how many seconds until the robots are clumped into a tree. Robots wrap around, so the
+// x positions repeat every width seconds and the y positions every height seconds. The tree is when both
+// are bunched up the most, so find the x and y times with the least spread and combine them with the
+// chinese remainder theorem.
+func (b day14Board) treeSeconds() (int, error) {
+	tightest := func(period int, axis func(r day14robot) (p, v int)) int {
+		best, bestVariance := 0, math.Inf(1)
+		for t := range period {
+			sum, sumSquares := 0.0, 0.0
+			for _, r := range b.robots {
+				p, v := axis(r)
+				x := float64(mathx.Mod(p+v*t, period))
+				sum += x
+				sumSquares += x * x
+			}
+			n := float64(len(b.robots))
+			if variance := sumSquares/n - (sum/n)*(sum/n); variance < bestVariance {
+				best, bestVariance = t, variance
+			}
+		}
+		return best
+	}
+
+	tx := tightest(b.width, func(r day14robot) (int, int) { return r.X, r.velocity.X })
+	ty := tightest(b.height, func(r day14robot) (int, int) { return r.Y, r.velocity.Y })
+	seconds, _, err := mathx.CRT([]int{tx, ty}, []int{b.width, b.height})
+	if err != nil {
+		return 0, fmt.Errorf("no time when the robots line up: %w", err)
+	}
+	return seconds, nil
 }
 
 // guess that the tree is in the middle 1/3rd
@@ -232,7 +312,7 @@ func (b day14Board) treeArea() (midUpperLeft, midLowerRight position) {
 	midTop := b.height / 3
 	midBottom := b.height - midTop
 
-	return position{midLeft, midTop}, position{midRight, midBottom}
+	return position{X: midLeft, Y: midTop}, position{X: midRight, Y: midBottom}
 }
 
 // return what percent of robots are in that square
@@ -241,7 +321,7 @@ func (b day14Board) tree() float64 {
 
 	midRobots := 0
 	for _, r := range b.robots {
-		if r.x >= b.midUpperLeft.x && r.x < b.midLowerRight.x && r.y >= b.midUpperLeft.y && r.y < b.midLowerRight.y {
+		if r.X >= b.midUpperLeft.X && r.X < b.midLowerRight.X && r.Y >= b.midUpperLeft.Y && r.Y < b.midLowerRight.Y {
 			midRobots++
 		}
 	}
@@ -251,18 +331,18 @@ func (b day14Board) tree() float64 {
 func (b *day14Board) move(moves int) {
 	for i := range b.robots {
 		r := &b.robots[i]
-		x, y := r.x+(r.velocity.x*moves), r.y+(r.velocity.y*moves)
+		p := r.position.Add(r.velocity.Scale(moves))
 		// account for loop arounds
-		x = x % b.width
-		y = y % b.height
-		if x < 0 {
-			x = b.width + x
+		p.X = p.X % b.width
+		p.Y = p.Y % b.height
+		if p.X < 0 {
+			p.X = b.width + p.X
 		}
-		if y < 0 {
-			y = b.height + y
+		if p.Y < 0 {
+			p.Y = b.height + p.Y
 		}
 		// move the robot
-		r.x, r.y = x, y
+		r.position = p
 	}
 }
 
@@ -275,15 +355,15 @@ func (b *day14Board) safetyFactor() int {
 
 // clockwise upper left to lower left quadrant
 func (b *day14Board) quadrants() (q1, q2, q3, q4 int) {
-	mid := position{b.width / 2, b.height / 2}
+	mid := position{X: b.width / 2, Y: b.height / 2}
 	for i, r := range b.robots {
 		_ = i
-		if r.x == mid.x || r.y == mid.y {
+		if r.X == mid.X || r.Y == mid.Y {
 			// doesn't count
 			continue
 		}
-		if r.x < mid.x {
-			if r.y < mid.y {
+		if r.X < mid.X {
+			if r.Y < mid.Y {
 				// slog.Debug(fmt.Sprintf("r%d (%v) in upper left", i, r.position))
 				q1++
 			} else {
@@ -291,7 +371,7 @@ func (b *day14Board) quadrants() (q1, q2, q3, q4 int) {
 				q4++
 			}
 		} else {
-			if r.y < mid.y {
+			if r.Y < mid.Y {
 				// slog.Debug(fmt.Sprintf("r%d (%v) in upper right", i, r.position))
 				q2++
 			} else {
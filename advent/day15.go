@@ -1,13 +1,11 @@
 package advent
 
 import (
-	"bufio"
 	"fmt"
-	"os"
-	"strconv"
-	"strings"
 	"time"
 
+	"github.com/sirgwain/advent-of-code-2024/advent/geom"
+	"github.com/sirgwain/advent-of-code-2024/advent/parse"
 	"github.com/sirgwain/advent-of-code-2024/advent/tui"
 )
 
@@ -36,12 +34,10 @@ type day15Board struct {
 
 	move     int
 	solution int
+	// the final gps sum, if we know it, so we can redact the solution as we get close
+	finalSolution int
 }
 
-var robotRender = robotStyle.Render("@")
-var wallRender = wallStyle.Render("#")
-var boxRender = boxStyle.Render("O")
-
 // Run is the main entry point for a day. It reads the input file and runs the part
 func (d *Day15) Run(part int, filename string, opts ...Option) error {
 	d.Options = newRun(opts...)
@@ -60,43 +56,51 @@ func (d *Day15) Run(part int, filename string, opts ...Option) error {
 	}
 }
 
+// Validate reads the input file and checks its format without solving
+func (d *Day15) Validate(filename string) error {
+	_, err := d.readInput(filename)
+	return err
+}
+
+// readInput reads the warehouse map section followed by the moves section
 func (d *Day15) readInput(filename string) (day15Input, error) {
-	file, err := os.Open(filename)
+	file, err := parse.ReadFile(filename)
 	if err != nil {
-		return day15Input{}, fmt.Errorf("error opening file: %w", err)
+		return day15Input{}, err
 	}
-	defer file.Close()
 
-	var input day15Input
+	sections, err := file.SectionsN(2)
+	if err != nil {
+		return day15Input{}, err
+	}
 
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := scanner.Bytes()
-		// skip empties
-		if strings.TrimSpace(string(line)) == "" {
-			continue
-		}
-		if line[0] == '#' { // wall means board, read as runes
-			input.board = append(input.board, []rune(string(line)))
+	var input day15Input
+	for _, line := range sections[0] {
+		row := []rune(line.Text)
+		if len(input.board) > 0 && len(row) != len(input.board[0]) {
+			return day15Input{}, line.Errorf(0, "row has %d columns, expected %d", len(row), len(input.board[0]))
 		}
-		// everything else is directions
-		for _, r := range line {
-			switch rune(r) {
-			case '>':
-				input.moves = append(input.moves, directionRight)
-			case '<':
-				input.moves = append(input.moves, directionLeft)
-			case '^':
-				input.moves = append(input.moves, directionUp)
-			case 'v':
-				input.moves = append(input.moves, directionDown)
+		input.board = append(input.board, row)
+	}
+
+	// moves can be split across many lines
+	for _, line := range sections[1] {
+		for col, r := range []rune(line.Text) {
+			dir, err := geom.ParseDirection(r)
+			if err != nil {
+				return day15Input{}, line.Errorf(col+1, "invalid move: %w", err)
 			}
+			input.moves = append(input.moves, dir)
 		}
 	}
 
 	input.height = len(input.board)
 	input.width = len(input.board[0])
-	input.robot = input.robotPosition()
+	robot, ok := input.robotPosition()
+	if !ok {
+		return day15Input{}, sections[0][0].Errorf(0, "no robot @ on the map")
+	}
+	input.robot = robot
 	return input, nil
 }
 
@@ -124,41 +128,45 @@ func (d *Day15) part1(input day15Input) error {
 	board.solve()
 
 	fmt.Printf("\n%s\n\n", board.view())
-	fmt.Printf("%s\n", board.viewSolution())
+	fmt.Printf("%s\n", board.viewSolution(noRedaction))
 	return nil
 }
 
 func (d *Day15) part2(input day15Input) error {
 	board := input
 	fmt.Println(board.view())
-	fmt.Println(board.viewSolution())
+	fmt.Println(board.viewSolution(noRedaction))
 
 	return nil
 }
 
 func (d *Day15) visual(input day15Input) error {
 	// create a bubbletea program
-	p := tui.NewViewportProgram(tui.NewModel("Day 15"))
+	p := tui.NewViewportProgram(tui.NewModel("Day 15").WithRenderMode(d.RenderMode))
+
+	// find the solution so we can hide it from the output
+	silentBoard := input
+	silentBoard.board = duplicate2DSlice(input.board)
+	silentBoard.solve()
 
 	board := input
+	board.finalSolution = silentBoard.solution
 
 	// run the solver in a gouroutine and Send a message to the bubbletea program to update the viewport
 	// on each step
 	go func() {
-		for {
-
-			// update the UI
-			board.onStep = func() {
-				content := fmt.Sprintf("%s\n%s", board.view(), board.viewSolution())
-				p.Send(tui.UpdateViewport(content, board.width))
-
-				if d.Options.Delay > 0 {
-					time.Sleep(time.Millisecond * time.Duration(d.Options.Delay))
-				}
+		// update the UI
+		board.onStep = func() {
+			p.Send(tui.UpdateBoard(&board, board.viewSolution(d.redaction())))
 
+			if d.Options.Delay > 0 {
+				time.Sleep(time.Millisecond * time.Duration(d.Options.Delay))
 			}
+		}
 
-			board.solve()
+		board.solve()
+		if d.RedactSolution {
+			p.Send(tui.Reveal(tui.UpdateBoard(&board, board.viewSolution(noRedaction))))
 		}
 	}()
 
@@ -169,20 +177,20 @@ func (d *Day15) visual(input day15Input) error {
 
 	// output the board and final result before exiting the program
 	fmt.Println(board.view())
-	fmt.Println(board.viewSolution())
+	fmt.Println(board.viewSolution(noRedaction))
 	return nil
 }
 
-func (b day15Board) robotPosition() position {
+func (b day15Board) robotPosition() (position, bool) {
 	for y := 0; y < b.height; y++ {
 		for x := 0; x < b.width; x++ {
 			switch b.board[y][x] {
 			case '@':
-				return position{x, y}
+				return position{X: x, Y: y}, true
 			}
 		}
 	}
-	return position{}
+	return position{}, false
 }
 
 func (b day15Board) gps() int {
@@ -210,32 +218,30 @@ func (b *day15Board) solve() {
 }
 
 func (b *day15Board) moveRobot(dir direction) {
-	x, y := b.robot.x, b.robot.y
-	dx, dy := dir.offsetMultiplier()
-	tx, ty := x+dx, y+dy
-	target := getBoardValue(tx, ty, b.board)
-	switch target {
+	x, y := b.robot.X, b.robot.Y
+	target := b.robot.Move(dir)
+	tx, ty := target.X, target.Y
+	switch getBoardValue(tx, ty, b.board) {
 	case wall: // wall, do nothing
 		return
 	case empty: // empty space, move
 		b.board[ty][tx] = robot
 		b.board[y][x] = empty
-		b.robot.x, b.robot.y = tx, ty
+		b.robot.X, b.robot.Y = tx, ty
 	case box: // box, try and move it
 		if b.moveBox(tx, ty, dir) {
 			b.board[ty][tx] = robot
 			b.board[y][x] = empty
-			b.robot.x, b.robot.y = tx, ty
+			b.robot.X, b.robot.Y = tx, ty
 		}
 	}
 	b.move++
 }
 
 func (b *day15Board) moveBox(x, y int, dir direction) bool {
-	dx, dy := dir.offsetMultiplier()
-	tx, ty := x+dx, y+dy
-	target := getBoardValue(tx, ty, b.board)
-	switch target {
+	target := position{X: x, Y: y}.Move(dir)
+	tx, ty := target.X, target.Y
+	switch getBoardValue(tx, ty, b.board) {
 	case wall: // wall, do nothing
 		return false
 	case empty: // empty space, move
@@ -253,26 +259,22 @@ func (b *day15Board) moveBox(x, y int, dir direction) bool {
 }
 
 func (b day15Board) view() string {
-	var sb strings.Builder
+	return day15Renderer.RenderRows(b.board)
+}
 
-	for y := 0; y < b.height; y++ {
-		for x := 0; x < b.width; x++ {
-			switch b.board[y][x] {
-			case wall:
-				sb.WriteString(wallRender)
-			case box:
-				sb.WriteString(boxRender)
-			case robot:
-				sb.WriteString(robotRender)
-			default:
-				sb.WriteRune(empty)
-			}
-		}
-		sb.WriteString("\n")
-	}
+// Size and Cell make the board a tui.Board, Focus keeps the robot centered when following
+func (b *day15Board) Size() (width, height int) {
+	return b.width, b.height
+}
 
-	return sb.String()
+func (b *day15Board) Focus() (x, y int) {
+	return b.robot.X, b.robot.Y
 }
-func (b day15Board) viewSolution() string {
-	return fmt.Sprintf("Move %d, Solution: %s", b.move, solutionStyle.Render(strconv.Itoa(b.solution)))
+
+func (b *day15Board) Cell(x, y int) tui.Cell {
+	return day15Renderer.Cell(position{X: x, Y: y}, b.board[y][x])
+}
+
+func (b day15Board) viewSolution(redact redaction) string {
+	return fmt.Sprintf("Move %d, Solution: %s", b.move, redact.render(b.solution, b.finalSolution))
 }
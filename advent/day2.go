@@ -1,17 +1,77 @@
 package advent
 
 import (
-	"bufio"
 	"fmt"
-	"os"
+	"regexp"
 	"strconv"
-	"strings"
+
+	"github.com/sirgwain/advent-of-code-2024/advent/parse"
 )
 
 type Day2 struct {
+	*Options
+}
+
+// day2Reason is why a report is unsafe
+type day2Reason int
+
+const (
+	day2Safe day2Reason = iota
+	// two levels are more than 3 apart
+	day2TooLarge
+	// two levels are the same
+	day2Unchanged
+	// the levels switched between increasing and decreasing
+	day2Reversed
+)
+
+func (r day2Reason) String() string {
+	switch r {
+	case day2Safe:
+		return "safe"
+	case day2TooLarge:
+		return "too large"
+	case day2Unchanged:
+		return "not decreasing or increasing"
+	case day2Reversed:
+		return "changed direction"
+	}
+	return "unknown"
+}
+
+// day2Direction is whether a report's levels are going up or down
+type day2Direction int
+
+const (
+	day2Unknown day2Direction = iota
+	day2Increasing
+	day2Decreasing
+)
+
+func (dir day2Direction) String() string {
+	switch dir {
+	case day2Increasing:
+		return "increasing"
+	case day2Decreasing:
+		return "decreasing"
+	}
+	return "unknown"
+}
+
+// day2Verdict is the result of checking a report
+type day2Verdict struct {
+	safe   bool
+	reason day2Reason
+	// pair is the index of the two levels that made the report unsafe
+	pair [2]int
+	// direction is the direction of the levels, up to the unsafe pair
+	direction day2Direction
+	// removed is the index of the level the dampener removed, or -1
+	removed int
 }
 
 func (d *Day2) Run(part int, filename string, opts ...Option) error {
+	d.Options = newRun(opts...)
 	switch part {
 	case 1:
 		return d.part1(filename)
@@ -22,35 +82,33 @@ func (d *Day2) Run(part int, filename string, opts ...Option) error {
 	}
 }
 
+// Validate reads the input file and checks its format without solving
+func (d *Day2) Validate(filename string) error {
+	_, err := d.readInput(filename)
+	return err
+}
+
+var day2Line = regexp.MustCompile(`^\d+(\s+\d+)*$`)
+
+// readInput reads one report of levels per line
 func (d *Day2) readInput(filename string) ([][]int, error) {
-	file, err := os.Open(filename)
+	file, err := parse.ReadFile(filename)
 	if err != nil {
-		return nil, fmt.Errorf("error opening file: %w", err)
+		return nil, err
 	}
-	defer file.Close()
 
 	var reports [][]int
-
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := scanner.Text()
-		var levels []int
-
-		parts := strings.Fields(line)
-		for _, part := range parts {
-			num, err := strconv.Atoi(part)
-			if err != nil {
-				return nil, fmt.Errorf("failed to read number from line: %s %w", line, err)
-			}
-			levels = append(levels, num)
+	for _, line := range file.Lines {
+		if err := line.Expect(day2Line); err != nil {
+			return nil, err
+		}
+		levels, err := line.Ints()
+		if err != nil {
+			return nil, err
 		}
 		reports = append(reports, levels)
 	}
 
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("error reading file: %w", err)
-	}
-
 	return reports, nil
 }
 
@@ -62,17 +120,18 @@ func (d *Day2) part1(filename string) error {
 	}
 
 	safeReports := 0
-	for _, report := range reports {
+	for i, report := range reports {
 		// make sure they are all increasing/decreasing at the same level
-		safe := d.checkLevels(report)
-
-		if safe {
+		verdict := d.checkLevels(report)
+		if verdict.safe {
 			safeReports++
-			fmt.Printf("%v: Safe\n", report)
+		}
+		if d.Explain {
+			fmt.Printf("report %d: %s\n", i, verdict.view(report))
 		}
 	}
 
-	fmt.Printf("day2: %s: %d reports are safe", filename, safeReports)
+	fmt.Printf("day2: %s: %s reports are safe\n", filename, solutionStyle.Render(strconv.Itoa(safeReports)))
 
 	return nil
 }
@@ -86,56 +145,131 @@ func (d *Day2) part2(filename string) error {
 
 	safeReports := 0
 	for i, report := range reports {
-		fmt.Printf("report %d: ", i)
-		// make sure they are all increasing/decreasing at the same level
-		safe := d.checkLevels(report)
-
-		if !safe {
-			// try by removing a level each time
-			for li := 0; li < len(report); li++ {
-				fmt.Printf("report %d (without item %d): ", i, li)
-
-				safe = d.checkLevels(removeIndex(report, li))
-				if safe {
-					break
-				}
-			}
-		}
-
-		if safe {
+		verdict := d.dampen(report)
+		if verdict.safe {
 			safeReports++
-			fmt.Printf("%v: Safe\n", report)
+		}
+		if d.Explain {
+			fmt.Printf("report %d: %s\n", i, verdict.view(report))
 		}
 	}
 
-	fmt.Printf("day2: %s: %d reports are safe", filename, safeReports)
+	fmt.Printf("day2: %s: %s reports are safe\n", filename, solutionStyle.Render(strconv.Itoa(safeReports)))
 
 	return nil
 }
 
-func (d *Day2) checkLevels(report []int) bool {
-	var lastDiff *int
+// checkLevels checks that a report's levels all increase or all decrease by 1 to 3
+func (d *Day2) checkLevels(report []int) day2Verdict {
+	verdict := day2Verdict{safe: true, removed: -1}
 	for j := 1; j < len(report); j++ {
-		l0 := report[j-1]
-		l1 := report[j]
-		diff := int(l0 - l1)
-		if diff > 3 || diff < -3 {
-			fmt.Printf("%v: Unsafe because level %d -> %d too large %d\n", report, l0, l1, diff)
-			return false
+		direction, reason := day2Step(report[j-1], report[j], verdict.direction)
+		if reason != day2Safe {
+			verdict.safe = false
+			verdict.reason = reason
+			verdict.pair = [2]int{j - 1, j}
+			return verdict
 		}
-		if diff == 0 {
-			fmt.Printf("%v: Unsafe because level %d -> %d not decreasing or increasing\n", report, l0, l1)
-			return false
+		verdict.direction = direction
+	}
+	return verdict
+}
+
+// day2Step checks the step from one level to the next, given the direction so far. It returns the
+// direction after the step, or why the step is unsafe.
+func day2Step(from, to int, direction day2Direction) (day2Direction, day2Reason) {
+	diff := to - from
+	switch {
+	case diff > 3 || diff < -3:
+		return direction, day2TooLarge
+	case diff == 0:
+		return direction, day2Unchanged
+	case diff > 0 && direction == day2Decreasing, diff < 0 && direction == day2Increasing:
+		return direction, day2Reversed
+	case diff > 0:
+		return day2Increasing, day2Safe
+	default:
+		return day2Decreasing, day2Safe
+	}
+}
+
+// day2Path is one safe way through the start of a report: the index of the last level kept, or -1
+// if there isn't one yet, the direction so far, and the index of the level removed, or -1
+type day2Path struct {
+	last      int
+	direction day2Direction
+	removed   int
+}
+
+// dampen checks a report, and if it's unsafe, finds a level to remove to make it safe. It reads the
+// report once, keeping every safe path through the levels so far with at most one level removed. Paths
+// that end on the same level going the same way are merged, so there are never more than a handful.
+// When more than one level could be removed, the last one is.
+func (d *Day2) dampen(report []int) day2Verdict {
+	verdict := day2Verdict{safe: true, removed: -1}
+	paths := []day2Path{{last: -1, removed: -1}}
+	for j := range report {
+		var next []day2Path
+		for _, path := range paths {
+			if path.removed == -1 {
+				next = addDay2Path(next, day2Path{last: path.last, direction: path.direction, removed: j})
+			}
+			if path.last == -1 {
+				next = addDay2Path(next, day2Path{last: j, direction: path.direction, removed: path.removed})
+				continue
+			}
+
+			direction, reason := day2Step(report[path.last], report[j], path.direction)
+			if reason == day2Safe {
+				next = addDay2Path(next, day2Path{last: j, direction: direction, removed: path.removed})
+			} else if path.removed == -1 {
+				// the report is unsafe without the dampener, explain why
+				verdict = day2Verdict{reason: reason, pair: [2]int{path.last, j}, direction: path.direction, removed: -1}
+			}
 		}
+		paths = next
+	}
+
+	// prefer not removing anything, then removing the last level we can
+	var best *day2Path
+	for i := range paths {
+		path := &paths[i]
+		if path.removed == -1 {
+			return day2Verdict{safe: true, direction: path.direction, removed: -1}
+		}
+		if best == nil || path.removed > best.removed {
+			best = path
+		}
+	}
+	if best == nil {
+		return verdict
+	}
+	return day2Verdict{safe: true, direction: best.direction, removed: best.removed}
+}
 
-		if lastDiff == nil {
-			lastDiff = &diff
-		} else {
-			if (*lastDiff > 0 && diff < 0) || (*lastDiff < 0 && diff > 0) {
-				fmt.Printf("%v: Unsafe because level %d -> %d is %d, last was %d\n", report, l0, l1, diff, *lastDiff)
-				return false
+// addDay2Path adds a path unless there is already one ending on the same level going the same way,
+// with or without a level removed. If there is, the path that removed the later level is kept.
+func addDay2Path(paths []day2Path, path day2Path) []day2Path {
+	for i, p := range paths {
+		if p.last == path.last && p.direction == path.direction && (p.removed == -1) == (path.removed == -1) {
+			if path.removed > p.removed {
+				paths[i] = path
 			}
+			return paths
 		}
 	}
-	return true
+	return append(paths, path)
+}
+
+// view explains a verdict, like [1 2 7 8 9] unsafe: level 2 -> 7 too large (5)
+func (v day2Verdict) view(report []int) string {
+	if v.safe {
+		if v.removed != -1 {
+			return fmt.Sprintf("%v %s without level %d (%d), %s", report, correctResultStyle.Render("safe"), v.removed, report[v.removed], v.direction)
+		}
+		return fmt.Sprintf("%v %s, %s", report, correctResultStyle.Render("safe"), v.direction)
+	}
+
+	l0, l1 := report[v.pair[0]], report[v.pair[1]]
+	return fmt.Sprintf("%v %s: level %d -> %d %s (%d), %s", report, incorrectResultStyle.Render("unsafe"), l0, l1, v.reason, l1-l0, v.direction)
 }
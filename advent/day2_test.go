@@ -0,0 +1,63 @@
+package advent
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestDay2_dampen(t *testing.T) {
+	tests := []struct {
+		name    string
+		report  []int
+		want    bool
+		removed int
+	}{
+		{"safe decreasing", []int{7, 6, 4, 2, 1}, true, -1},
+		{"too large", []int{1, 2, 7, 8, 9}, false, -1},
+		{"too large decreasing", []int{9, 7, 6, 2, 1}, false, -1},
+		{"changed direction", []int{1, 3, 2, 4, 5}, true, 2},
+		{"unchanged", []int{8, 6, 4, 4, 1}, true, 3},
+		{"safe increasing", []int{1, 3, 6, 7, 9}, true, -1},
+		{"first level sets the wrong direction", []int{3, 4, 3, 2, 1}, true, 0},
+		{"last level", []int{1, 2, 3, 4, 9}, true, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &Day2{}
+			got := d.dampen(tt.report)
+			if got.safe != tt.want || (got.safe && got.removed != tt.removed) {
+				t.Errorf("Day2.dampen() = %v removed %d, want %v removed %d", got.safe, got.removed, tt.want, tt.removed)
+			}
+		})
+	}
+}
+
+// TestDay2_dampenBruteForce checks the dampener against removing every level in turn
+func TestDay2_dampenBruteForce(t *testing.T) {
+	d := &Day2{}
+	bruteForce := func(report []int) bool {
+		if d.checkLevels(report).safe {
+			return true
+		}
+		for i := range report {
+			if d.checkLevels(removeIndex(report, i)).safe {
+				return true
+			}
+		}
+		return false
+	}
+
+	rng := rand.New(rand.NewSource(2))
+	for range 10000 {
+		report := make([]int, 2+rng.Intn(7))
+		report[0] = rng.Intn(20)
+		for i := 1; i < len(report); i++ {
+			// mostly small steps so plenty of reports are safe or nearly safe
+			report[i] = max(0, report[i-1]+rng.Intn(11)-5)
+		}
+
+		if got, want := d.dampen(report).safe, bruteForce(report); got != want {
+			t.Fatalf("Day2.dampen(%v) = %v, brute force = %v", report, got, want)
+		}
+	}
+}
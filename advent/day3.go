@@ -1,22 +1,54 @@
 package advent
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"os"
-	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sirgwain/advent-of-code-2024/advent/tui"
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
 	"golang.org/x/text/number"
 )
 
 type Day3 struct {
+	*Options
+}
+
+// day3Kind is the kind of instruction found in the corrupted memory
+type day3Kind int
+
+const (
+	day3Mul day3Kind = iota
+	day3Do
+	day3Dont
+)
+
+// day3Token is an instruction and where it is in the memory
+type day3Token struct {
+	kind day3Kind
+	// start and end are the byte offsets of the instruction, end is exclusive
+	start int
+	end   int
+	// x and y are the arguments to mul
+	x int
+	y int
+}
+
+// day3Step is a token after it runs, with the total so far
+type day3Step struct {
+	day3Token
+	executed bool
+	total    int
 }
 
 func (d *Day3) Run(part int, filename string, opts ...Option) error {
+	d.Options = newRun(opts...)
 	switch part {
 	case 1:
 		return d.part1(filename)
@@ -27,6 +59,30 @@ func (d *Day3) Run(part int, filename string, opts ...Option) error {
 	}
 }
 
+// Validate reads the input file and checks its format without solving
+func (d *Day3) Validate(filename string) error {
+	_, err := d.readInput(filename)
+	return err
+}
+
+// RunVisual is like Run, but it steps through the instructions in a bubbletea program
+func (d *Day3) RunVisual(part int, filename string, opts ...Option) error {
+	d.Options = newRun(opts...)
+	input, err := d.readInput(filename)
+	if err != nil {
+		return err
+	}
+
+	switch part {
+	case 1:
+		return d.traceVisual("Day 3 - Part 1", input, false)
+	case 2:
+		return d.traceVisual("Day 3 - Part 2", input, true)
+	default:
+		return fmt.Errorf("part %d not valid", part)
+	}
+}
+
 var p = message.NewPrinter(language.English)
 
 func (d *Day3) readInput(filename string) ([]byte, error) {
@@ -53,12 +109,12 @@ func (d *Day3) part1(filename string) error {
 		return err
 	}
 
-	result, err := d.evaluateInput(input)
-	if err != nil {
-		return err
+	steps := d.interpret(day3Tokenize(input), false)
+	if d.Explain {
+		d.trace(input, steps)
 	}
 
-	fmt.Printf("day3a: %s: %d", filename, result)
+	fmt.Printf("day3a: %s: %d\n", filename, day3Total(steps))
 
 	return nil
 }
@@ -70,63 +126,219 @@ func (d *Day3) part2(filename string) error {
 		return err
 	}
 
-	window := input[:]
-	result := 0
-	offsetIndex := 0
-	for {
-		// find the don't() and match up to it
-		donotIndex := strings.Index(string(window), "don't()")
-		fmt.Printf("evaluating: %d-%d\n", offsetIndex, offsetIndex+donotIndex)
-		subResult, err := d.evaluateInput(window[:donotIndex])
-		if err != nil {
-			return err
+	steps := d.interpret(day3Tokenize(input), true)
+	if d.Explain {
+		d.trace(input, steps)
+	}
+
+	fmt.Printf("day3b: %s: %d\n", filename, day3Total(steps))
+
+	return nil
+}
+
+// day3Tokenize scans the memory for instructions, skipping the corrupted bytes between them
+//
+//	mul(X,Y) with 1-3 digit numbers
+//	do()
+//	don't()
+func day3Tokenize(input []byte) []day3Token {
+	var tokens []day3Token
+	for i := 0; i < len(input); {
+		token, ok := day3Scan(input, i)
+		if !ok {
+			i++
+			continue
 		}
-		result += subResult
-		p.Printf("total: %v\n", number.Decimal(result))
-
-		// find the next do
-		doIndex := strings.Index(string(window[donotIndex:]), "do()")
-		if doIndex == -1 {
-			fmt.Printf("no more do()s after %d\n", offsetIndex+donotIndex)
-			break
+		tokens = append(tokens, token)
+		i = token.end
+	}
+	return tokens
+}
+
+// day3Scan reads an instruction at start, or returns false if there isn't one
+func day3Scan(input []byte, start int) (day3Token, bool) {
+	rest := input[start:]
+	switch {
+	case bytes.HasPrefix(rest, []byte("don't()")):
+		return day3Token{kind: day3Dont, start: start, end: start + len("don't()")}, true
+	case bytes.HasPrefix(rest, []byte("do()")):
+		return day3Token{kind: day3Do, start: start, end: start + len("do()")}, true
+	case bytes.HasPrefix(rest, []byte("mul(")):
+		i := start + len("mul(")
+		x, i, ok := day3Number(input, i)
+		if !ok || i >= len(input) || input[i] != ',' {
+			return day3Token{}, false
 		}
-		// reset the window to start at the do()
-		fmt.Printf("skipping %d-%d\n%s\n", offsetIndex+donotIndex, offsetIndex+donotIndex+doIndex, window[donotIndex:donotIndex+doIndex+4])
-		window = window[donotIndex+doIndex:]
-		offsetIndex += doIndex
+		y, i, ok := day3Number(input, i+1)
+		if !ok || i >= len(input) || input[i] != ')' {
+			return day3Token{}, false
+		}
+		return day3Token{kind: day3Mul, start: start, end: i + 1, x: x, y: y}, true
 	}
-	p.Printf("total: %v\n", number.Decimal(result))
+	return day3Token{}, false
+}
 
-	fmt.Printf("day3b: %s: %d\n", filename, result)
+// day3Number reads a 1-3 digit number at i and returns it and the offset after it
+func day3Number(input []byte, i int) (int, int, bool) {
+	num, digits := 0, 0
+	for ; i < len(input) && digits < 3 && input[i] >= '0' && input[i] <= '9'; i++ {
+		num = num*10 + int(input[i]-'0')
+		digits++
+	}
+	if digits == 0 || (i < len(input) && input[i] >= '0' && input[i] <= '9') {
+		// no number, or too many digits
+		return 0, i, false
+	}
+	return num, i, true
+}
 
-	return nil
+// interpret runs the instructions. If conditionals is set, don't() disables muls until the next do()
+func (d *Day3) interpret(tokens []day3Token, conditionals bool) []day3Step {
+	steps := make([]day3Step, len(tokens))
+	enabled := true
+	total := 0
+	for i, token := range tokens {
+		step := day3Step{day3Token: token}
+		switch token.kind {
+		case day3Do:
+			enabled = true
+			step.executed = conditionals
+		case day3Dont:
+			enabled = !conditionals
+			step.executed = conditionals
+		case day3Mul:
+			if enabled {
+				total += token.x * token.y
+				step.executed = true
+			}
+		}
+		step.total = total
+		steps[i] = step
+	}
+	return steps
 }
 
-func (d *Day3) evaluateInput(input []byte) (int, error) {
-	pattern := `mul\((\d{1,3}),(\d{1,3})\)`
-	re, err := regexp.Compile(pattern)
-	if err != nil {
-		return 0, fmt.Errorf("bad regex %s %w", pattern, err)
+// day3Total is the total after every instruction runs
+func day3Total(steps []day3Step) int {
+	if len(steps) == 0 {
+		return 0
+	}
+	return steps[len(steps)-1].total
+}
+
+// trace prints the memory with each instruction highlighted, then what each one did
+func (d *Day3) trace(input []byte, steps []day3Step) {
+	fmt.Printf("%s\n\n", day3ViewMemory(input, steps, -1))
+	for _, step := range steps {
+		fmt.Printf("%6d: %s\n", step.start, step.view(input))
 	}
+	fmt.Println()
+}
+
+func (d *Day3) traceVisual(title string, input []byte, conditionals bool) error {
+	// create a bubbletea program
+	p := tui.NewViewportProgram(tui.NewModel(title))
+
+	// we know the answer before we step through it, so it can be redacted
+	steps := d.interpret(day3Tokenize(input), conditionals)
+	solution := day3Total(steps)
+
+	// the real input has hundreds of instructions, only draw a frame every few
+	frameEvery := max(1, len(steps)/200)
 
-	// Find all matches
-	matches := re.FindAllSubmatch(input, -1)
+	view := func(current int, redact redaction) string {
+		total := 0
+		if current >= 0 {
+			total = steps[current].total
+		}
+		return fmt.Sprintf("%s\n\nTotal: %s", day3ViewMemory(input, steps[:current+1], current), redact.render(total, solution))
+	}
 
-	result := 0
-	for _, mul := range matches {
-		x, err := strconv.Atoi(string(mul[1]))
-		if err != nil {
-			return 0, fmt.Errorf("match not a number %s %s %w", mul, mul[1], err)
+	go func() {
+		for i := range steps {
+			if (i+1)%frameEvery == 0 || i == len(steps)-1 {
+				p.Send(tui.UpdateViewport(view(i, d.redaction()), day3MemoryWidth))
+				if d.Delay != 0 {
+					time.Sleep(time.Duration(d.Delay) * time.Millisecond)
+				}
+			}
 		}
+		if d.RedactSolution {
+			p.Send(tui.Reveal(tui.UpdateViewport(view(len(steps)-1, noRedaction), day3MemoryWidth)))
+		}
+	}()
+
+	if _, err := p.Run(); err != nil {
+		return fmt.Errorf("could not start program: %v", err)
+	}
+
+	fmt.Printf("Total: %s\n", solutionStyle.Render(strconv.Itoa(solution)))
+	return nil
+}
 
-		y, err := strconv.Atoi(string(mul[2]))
-		if err != nil {
-			return 0, fmt.Errorf("match not a number %s %s %w", mul, mul[2], err)
+// view describes what an instruction did, like mul(2,4) = 8, total: 8
+func (s day3Step) view(input []byte) string {
+	text := string(input[s.start:s.end])
+	switch {
+	case s.kind != day3Mul:
+		if s.executed {
+			return conditionStyle.Render(text)
 		}
+		return skippedStyle.Render(text + " ignored")
+	case s.executed:
+		return fmt.Sprintf("%s = %d, total: %s", executedStyle.Render(text), s.x*s.y, p.Sprintf("%v", number.Decimal(s.total)))
+	}
+	return skippedStyle.Render(text + " skipped")
+}
+
+// day3MemoryWidth is where the memory is wrapped when it's drawn
+const day3MemoryWidth = 100
+
+// day3ViewMemory draws the memory, wrapped to day3MemoryWidth, with the instructions that ran so far
+// highlighted by whether they were executed or skipped. current is the instruction running now, or -1.
+func day3ViewMemory(input []byte, steps []day3Step, current int) string {
+	var sb strings.Builder
+	col := 0
 
-		result += x * y
-		p.Printf("evaluated %s, subtotal: %v\n", mul, number.Decimal(result))
+	// write text one wrapped line at a time so every line is styled on its own
+	write := func(text []byte, style lipgloss.Style) {
+		for len(text) > 0 {
+			if text[0] == '\n' {
+				sb.WriteByte('\n')
+				col = 0
+				text = text[1:]
+				continue
+			}
+			if col == day3MemoryWidth {
+				sb.WriteByte('\n')
+				col = 0
+			}
+			n := min(len(text), day3MemoryWidth-col)
+			if newline := bytes.IndexByte(text[:n], '\n'); newline != -1 {
+				n = newline
+			}
+			sb.WriteString(style.Render(string(text[:n])))
+			col += n
+			text = text[n:]
+		}
 	}
 
-	return result, nil
+	last := 0
+	for i, step := range steps {
+		write(input[last:step.start], memoryStyle)
+
+		style := skippedStyle
+		switch {
+		case i == current:
+			style = currentStyle
+		case step.kind != day3Mul && step.executed:
+			style = conditionStyle
+		case step.executed:
+			style = executedStyle
+		}
+		write(input[step.start:step.end], style)
+		last = step.end
+	}
+	write(input[last:], memoryStyle)
+	return sb.String()
 }
@@ -0,0 +1,27 @@
+package advent
+
+import "testing"
+
+func TestDay3_interpret(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		conditionals bool
+		want         int
+	}{
+		{"part 1", "xmul(2,4)%&mul[3,7]!@^do_not_mul(5,5)+mul(32,64]then(mul(11,8)mul(8,5))", false, 161},
+		{"part 2", "xmul(2,4)&mul[3,7]!^don't()_mul(5,5)+mul(32,64](mul(11,8)undo()?mul(8,5))", true, 48},
+		{"no don't", "mul(2,4)mul(3,3)", true, 17},
+		{"don't at the end", "mul(2,4)don't()", true, 8},
+		{"too many digits", "mul(1234,5)mul(2,3)", false, 6},
+		{"nested", "mul(mul(2,3))", false, 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &Day3{}
+			if got := day3Total(d.interpret(day3Tokenize([]byte(tt.input)), tt.conditionals)); got != tt.want {
+				t.Errorf("Day3.interpret() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,15 +1,41 @@
 package advent
 
 import (
-	"bufio"
 	"fmt"
-	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirgwain/advent-of-code-2024/advent/tui"
+	"github.com/sirgwain/advent-of-code-2024/advent/wordsearch"
 )
 
 type Day4 struct {
+	*Options
+}
+
+// day4Board is the word search as the visualization sweeps across it
+type day4Board struct {
+	board   *Grid[rune]
+	cursor  position
+	matches []wordsearch.Match
+	// found is the index of the latest match covering each letter
+	found *SparseGrid[int]
+	// counts are the matches found so far for each pattern
+	counts   map[string]int
+	names    []string
+	solution int
 }
 
+// the words or patterns to find in each part, unless the Words option replaces them
+var (
+	day4Words    = []wordsearch.Pattern{wordsearch.MustWord("XMAS")}
+	day4XPattern = []wordsearch.Pattern{wordsearch.MustParsePattern("M.M/.A./S.S")}
+)
+
 func (d *Day4) Run(part int, filename string, opts ...Option) error {
+	d.Options = newRun(opts...)
 	switch part {
 	case 1:
 		return d.part1(filename)
@@ -20,68 +46,57 @@ func (d *Day4) Run(part int, filename string, opts ...Option) error {
 	}
 }
 
-// read day4 input as a series of lines
-func (d *Day4) readInput(filename string) ([]string, error) {
-	file, err := os.Open(filename)
-	if err != nil {
-		return nil, fmt.Errorf("error opening file: %w", err)
-	}
-	defer file.Close()
-
-	var input []string
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		input = append(input, scanner.Text())
-
-	}
-
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("error reading file: %w", err)
-	}
-
-	return input, nil
+// Validate reads the input file and checks its format without solving
+func (d *Day4) Validate(filename string) error {
+	_, err := d.readInput(filename)
+	return err
 }
 
-func (d *Day4) part1(filename string) error {
+// readInput reads the word search as a grid of letters
+func (d *Day4) readInput(filename string) (*Grid[rune], error) {
+	return readInputAsRuneGrid(filename)
+}
 
+// RunVisual is like Run, but it sweeps across the board in a bubbletea program, highlighting matches as they're found
+func (d *Day4) RunVisual(part int, filename string, opts ...Option) error {
+	d.Options = newRun(opts...)
 	input, err := d.readInput(filename)
 	if err != nil {
 		return err
 	}
 
-	var matches []day4Match
-	for y := 0; y < len(input); y++ {
-		for x := 0; x < len(input[y]); x++ {
-			matches = append(matches, findXmas("XMAS", x, y, input)...)
-		}
+	switch part {
+	case 1:
+		return d.searchVisual("Day 4 - Part 1", input, day4Words)
+	case 2:
+		return d.searchVisual("Day 4 - Part 2", input, day4XPattern)
+	default:
+		return fmt.Errorf("part %d not valid", part)
 	}
+}
 
-	output := make([][]rune, len(input))
-	for y := 0; y < len(input); y++ {
-		line := []rune(input[y])
-		for x := 0; x < len(input[y]); x++ {
-			line[x] = rune('.')
-		}
-		output[y] = line
+// searcher creates a search for the Words option, or the defaults if it isn't set
+func (d *Day4) searcher(defaults []wordsearch.Pattern) (*wordsearch.Searcher, error) {
+	if len(d.Words) == 0 {
+		return wordsearch.New(defaults...), nil
 	}
 
-	for _, match := range matches {
-		offsetX, offsetY := match.direction.offsetMultiplier()
-		for i, r := range match.match {
-			output[match.y+i*offsetY][match.x+i*offsetX] = r
+	patterns := make([]wordsearch.Pattern, len(d.Words))
+	for i, word := range d.Words {
+		pattern, err := wordsearch.ParsePattern(word)
+		if err != nil {
+			return nil, err
 		}
+		patterns[i] = pattern
 	}
+	return wordsearch.New(patterns...), nil
+}
 
-	for _, line := range output {
-		fmt.Println(string(line))
-	}
-
-	fmt.Printf("day4a: %s: %d\n\n", filename, len(matches))
-
-	return nil
+func (d *Day4) part1(filename string) error {
+	return d.search(filename, day4Words, "day4a")
 }
 
-// Day4b finds X-MASes
+// Day4b finds X-MASes, an X of MAS in any of 4 rotations
 // up
 // ====
 // M.M
@@ -106,89 +121,151 @@ func (d *Day4) part1(filename string) error {
 // .A.
 // M.S
 func (d *Day4) part2(filename string) error {
+	return d.search(filename, day4XPattern, "day4b")
+}
+
+// search finds the patterns and prints the board with the matches highlighted
+func (d *Day4) search(filename string, defaults []wordsearch.Pattern, label string) error {
 	input, err := d.readInput(filename)
 	if err != nil {
 		return err
 	}
 
-	var board [][]rune = make([][]rune, 0, len(input))
-	for _, line := range input {
-		board = append(board, []rune(line))
+	searcher, err := d.searcher(defaults)
+	if err != nil {
+		return err
+	}
+
+	matches := searcher.Find(input.Slices())
+
+	fmt.Println(day4View(input, matches))
+	fmt.Printf("%s: %s: %d\n\n", label, filename, len(matches))
+
+	return nil
+}
+
+// day4View draws the board with the letters of each match highlighted and every other letter hidden
+func day4View(board *Grid[rune], matches []wordsearch.Match) string {
+	found := NewSparseGrid[bool]()
+	for _, match := range matches {
+		for _, cell := range match.Cells {
+			found.Set(cell.Point, true)
+		}
 	}
+	return day4Renderer.Render(board, day4FoundRenderer.Over(found.Get))
+}
 
-	numMatches := 0
-	for y := 1; y < len(input)-1; y++ {
-		for x := 1; x < len(input[y])-1; x++ {
-			if board[y][x] == 'A' {
-				// check top M, bottom S
-				if board[y-1][x-1] == 'M' && board[y-1][x+1] == 'M' &&
-					board[y+1][x-1] == 'S' && board[y+1][x+1] == 'S' {
-					numMatches++
-				}
-				// check right M, left S
-				if board[y-1][x-1] == 'S' && board[y-1][x+1] == 'M' &&
-					board[y+1][x-1] == 'S' && board[y+1][x+1] == 'M' {
-					numMatches++
-				}
-				// check bottom M, top S
-				if board[y-1][x-1] == 'S' && board[y-1][x+1] == 'S' &&
-					board[y+1][x-1] == 'M' && board[y+1][x+1] == 'M' {
-					numMatches++
-				}
-				// check left M, right S
-				if board[y-1][x-1] == 'M' && board[y-1][x+1] == 'S' &&
-					board[y+1][x-1] == 'M' && board[y+1][x+1] == 'S' {
-					numMatches++
+func (d *Day4) searchVisual(title string, input *Grid[rune], defaults []wordsearch.Pattern) error {
+	searcher, err := d.searcher(defaults)
+	if err != nil {
+		return err
+	}
+
+	// find the matches up front, so the solution can be redacted, and reveal them as the cursor reaches them.
+	// Matches are found in the order the cursor sweeps the board.
+	matches := searcher.Find(input.Slices())
+	board := day4Board{board: input, found: NewSparseGrid[int](), counts: map[string]int{}, solution: len(matches)}
+	for _, pattern := range defaults {
+		board.names = append(board.names, pattern.Name)
+	}
+	if len(d.Words) > 0 {
+		board.names = d.Words
+	}
+
+	// create a bubbletea program, the gate pauses the sweep while we inspect the board
+	gate := tui.NewGate()
+	p := tui.NewViewportProgram(tui.NewModel(title).
+		WithRenderMode(d.RenderMode).
+		WithInspector(inspector(board.inspect), gate))
+
+	go func() {
+		defer gate.Done()
+		next := 0
+		for pos := range input.All() {
+			board.cursor = pos
+			found := false
+			for ; next < len(matches) && matches[next].Start == pos; next++ {
+				board.add(matches[next])
+				found = true
+			}
+
+			// update the UI at each match and the end of each row
+			if found || pos.X == input.Width()-1 {
+				p.Send(tui.UpdateBoard(&board, board.viewSolution(d.redaction())))
+				if d.Delay != 0 {
+					time.Sleep(time.Duration(d.Delay) * time.Millisecond)
 				}
 			}
+			gate.Step()
+		}
+		if d.RedactSolution {
+			p.Send(tui.Reveal(tui.UpdateBoard(&board, board.viewSolution(noRedaction))))
 		}
+	}()
+
+	if _, err := p.Run(); err != nil {
+		return fmt.Errorf("could not start program: %v", err)
 	}
 
-	fmt.Printf("day4b: %s: %d\n\n", filename, numMatches)
+	// output the board and final result before exiting the program
+	fmt.Println(day4View(input, board.matches))
+	fmt.Println(board.viewSolution(noRedaction))
 	return nil
 }
 
-type day4Match struct {
-	x         int
-	y         int
-	match     string
-	direction direction
+// add marks a match as found
+func (b *day4Board) add(match wordsearch.Match) {
+	b.matches = append(b.matches, match)
+	b.counts[match.Pattern.Name]++
+	for _, cell := range match.Cells {
+		b.found.Set(cell.Point, len(b.matches)-1)
+	}
 }
 
-// horizontal, vertical, diagonal - forwards and backwards
-// S . . S . . S
-// . A . A . A .
-// S A M X M A S
-// . A . A . A .
-// S . . S . . S
-const possibleXMasMatches int = 8
+// Size and Cell make the board a tui.Board, Focus keeps the cursor centered when following
+func (b *day4Board) Size() (width, height int) {
+	return b.board.Size()
+}
 
-func findXmas(matchString string, x, y int, input []string) []day4Match {
-	matchLength := len(matchString)
+func (b *day4Board) Focus() (x, y int) {
+	return b.cursor.X, b.cursor.Y
+}
 
-	possibilities := make([]string, possibleXMasMatches)
-	for i := 0; i < matchLength; i++ {
-		// check each direction
-		for dir := 0; dir < possibleXMasMatches; dir++ {
-			offsetX, offsetY := direction(dir).offsetMultiplier()
-			possibilities[dir] += getChar(x+i*offsetX, y+i*offsetY, input)
+// Cell colors each match on its own, with an arrow at its start pointing the way it reads
+func (b *day4Board) Cell(x, y int) tui.Cell {
+	p := position{X: x, Y: y}
+	cell := tui.Cell{Char: b.board.Get(p), Fg: letterStyle.GetForeground()}
+	if i, ok := b.found.Lookup(p); ok {
+		match := b.matches[i]
+		cell.Fg = theme.CategoryColor(i)
+		if match.Start == p {
+			cell.Char = match.Orientation.Direction.Char()
 		}
 	}
+	if p == b.cursor {
+		cell.Bg = midStyle.GetBackground()
+	}
+	return cell
+}
 
-	// count numMatches
-	numMatches := 0
-	var matches []day4Match
-	for dir, possibility := range possibilities {
-		if possibility == matchString {
-			numMatches++
-			matches = append(matches, day4Match{
-				x:         x,
-				y:         y,
-				match:     matchString,
-				direction: direction(dir),
-			})
+// inspect describes a letter and the matches that cover it
+func (b *day4Board) inspect(p position) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "letter: %s\n", string(b.board.Get(p)))
+	for i, match := range b.matches {
+		if slices.ContainsFunc(match.Cells, func(c wordsearch.Cell) bool { return c.Point == p }) {
+			fmt.Fprintf(&sb, "match %d: %s at %v reading %s\n", i+1, match.Pattern.Name, match.Start, match.Orientation)
 		}
 	}
+	return sb.String()
+}
 
-	return matches
+// viewSolution shows the count of each pattern and the total, like XMAS: 18, total: 18
+func (b *day4Board) viewSolution(redact redaction) string {
+	var sb strings.Builder
+	for _, name := range b.names {
+		fmt.Fprintf(&sb, "%s: %s, ", name, numberStyle.Render(strconv.Itoa(b.counts[name])))
+	}
+	fmt.Fprintf(&sb, "total: %s", redact.render(len(b.matches), b.solution))
+	return sb.String()
 }
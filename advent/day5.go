@@ -1,17 +1,22 @@
 package advent
 
 import (
-	"bufio"
+	"errors"
 	"fmt"
 	"os"
+	"regexp"
 	"strconv"
-	"strings"
+
+	"github.com/sirgwain/advent-of-code-2024/advent/graph"
+	"github.com/sirgwain/advent-of-code-2024/advent/parse"
 )
 
 type Day5 struct {
+	*Options
 }
 
 func (d *Day5) Run(part int, filename string, opts ...Option) error {
+	d.Options = newRun(opts...)
 	switch part {
 	case 1:
 		return d.part1(filename)
@@ -22,6 +27,12 @@ func (d *Day5) Run(part int, filename string, opts ...Option) error {
 	}
 }
 
+// Validate reads the input file and checks its format without solving
+func (d *Day5) Validate(filename string) error {
+	_, err := d.readInput(filename)
+	return err
+}
+
 type inputDay5 struct {
 	orderingRules [][2]int
 	pageUpdates   [][]int
@@ -32,51 +43,51 @@ type day5OrderKey struct {
 	after  int
 }
 
-// read day4 input as a series of lines
+var (
+	day5Rule   = regexp.MustCompile(`^\d+\|\d+$`)
+	day5Update = regexp.MustCompile(`^\d+(,\d+)*$`)
+)
+
+// readInput reads the ordering rules section, like 47|53, and the page updates section, like 75,47,61
 func (d *Day5) readInput(filename string) (inputDay5, error) {
-	file, err := os.Open(filename)
+	file, err := parse.ReadFile(filename)
 	if err != nil {
-		return inputDay5{}, fmt.Errorf("error opening file: %w", err)
+		return inputDay5{}, err
 	}
-	defer file.Close()
 
-	var input inputDay5
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-
-		line := scanner.Text()
-		if strings.Contains(line, "|") {
-			split := strings.Split(line, "|")
-			rule := [2]int{}
-			if len(split) != 2 {
-				return inputDay5{}, fmt.Errorf("line %s doesn't have the right values", line)
-			}
+	sections, err := file.SectionsN(2)
+	if err != nil {
+		return inputDay5{}, err
+	}
 
-			rule[0], err = strconv.Atoi(split[0])
-			if err != nil {
-				return inputDay5{}, fmt.Errorf("line %s invalid number %w", line, err)
-			}
-			rule[1], err = strconv.Atoi(split[1])
-			if err != nil {
-				return inputDay5{}, fmt.Errorf("line %s invalid number %w", line, err)
-			}
-			input.orderingRules = append(input.orderingRules, rule)
-		} else if strings.Contains(line, ",") {
-			split := strings.Split(line, ",")
-			pages := []int{}
-			for _, s := range split {
-				page, err := strconv.Atoi(s)
-				if err != nil {
-					return inputDay5{}, fmt.Errorf("line %s invalid number %w", line, err)
-				}
-				pages = append(pages, page)
-			}
-			input.pageUpdates = append(input.pageUpdates, pages)
+	var input inputDay5
+	for _, line := range sections[0] {
+		if err := line.Expect(day5Rule); err != nil {
+			return inputDay5{}, err
+		}
+		rule, err := line.IntsN(2)
+		if err != nil {
+			return inputDay5{}, err
 		}
+		input.orderingRules = append(input.orderingRules, [2]int{rule[0], rule[1]})
 	}
 
-	if err := scanner.Err(); err != nil {
-		return inputDay5{}, fmt.Errorf("error reading file: %w", err)
+	for _, line := range sections[1] {
+		if err := line.Expect(day5Update); err != nil {
+			return inputDay5{}, err
+		}
+		pages, err := line.Ints()
+		if err != nil {
+			return inputDay5{}, err
+		}
+		seen := make(map[int]bool, len(pages))
+		for _, page := range pages {
+			if seen[page] {
+				return inputDay5{}, line.Errorf(0, "page %d is in the update more than once", page)
+			}
+			seen[page] = true
+		}
+		input.pageUpdates = append(input.pageUpdates, pages)
 	}
 
 	return input, nil
@@ -89,11 +100,8 @@ func (d *Day5) part1(filename string) error {
 		return err
 	}
 
-	// make a map of before and after page rules
-	rules := make(map[day5OrderKey]bool)
-	for _, rule := range input.orderingRules {
-		rules[day5OrderKey{rule[0], rule[1]}] = true
-	}
+	rules := d.rules(input)
+	d.warnCycles(rules)
 
 	var valids [][]int
 	for _, update := range input.pageUpdates {
@@ -116,7 +124,7 @@ func (d *Day5) part1(filename string) error {
 
 	fmt.Printf("numValid: %s, total mids: %s\n", numberStyle.Render(strconv.Itoa(numValid)), solutionStyle.Render(strconv.Itoa(totalMids)))
 
-	return nil
+	return d.writeDOT(input, rules)
 }
 
 func (d *Day5) part2(filename string) error {
@@ -126,11 +134,8 @@ func (d *Day5) part2(filename string) error {
 		return err
 	}
 
-	// make a map of before and after page rules
-	rules := make(map[day5OrderKey]bool)
-	for _, rule := range input.orderingRules {
-		rules[day5OrderKey{rule[0], rule[1]}] = true
-	}
+	rules := d.rules(input)
+	d.warnCycles(rules)
 
 	var invalids [][]int
 	for _, update := range input.pageUpdates {
@@ -145,72 +150,113 @@ func (d *Day5) part2(filename string) error {
 
 	// sort the invalids
 	numInvalid := len(invalids)
+	numCycles := 0
 	totalMids := 0
 	for _, update := range invalids {
 		fmt.Printf("%s %v => ", incorrectResultStyle.Render("invalid"), update)
-		for {
-			valid, _ := d.eval(update, rules)
-			if valid {
-				break
+		fixed, err := d.repair(update, rules)
+		if err != nil {
+			var cycleErr *graph.CycleError[int]
+			if !errors.As(err, &cycleErr) {
+				return err
 			}
-			// not good yet, repair it
-			d.repair(update, rules)
+			// the rules for these pages contradict each other, so there is no order to fix it with
+			fmt.Printf("%s %v\n", incorrectResultStyle.Render("can't fix"), cycleErr)
+			numCycles++
+			continue
 		}
 
-		fmt.Printf("%v %s\n", update, correctResultStyle.Render("valid"))
+		fmt.Printf("%v %s\n", fixed, correctResultStyle.Render("valid"))
 
-		mid := update[len(update)/2]
+		mid := fixed[len(fixed)/2]
 		totalMids += mid
 	}
 
+	if numCycles > 0 {
+		fmt.Printf("%s %d updates have rules with cycles\n", incorrectResultStyle.Render("warning:"), numCycles)
+	}
 	fmt.Printf("Num Invalid: %s, total mids: %s\n", numberStyle.Render(strconv.Itoa(numInvalid)), solutionStyle.Render(strconv.Itoa(totalMids)))
 
-	return nil
+	return d.writeDOT(input, rules)
 }
 
-func (d *Day5) eval(update []int, rules map[day5OrderKey]bool) (bool, *day5OrderKey) {
-	for i, page := range update {
-		if i < len(update)-1 {
-			// for 75,47,61,53,29
-			// 47 must not come before 75
-			rule := day5OrderKey{update[i+1], page}
-			if rules[rule] {
-				return false, &rule
-			}
-		}
-		if i > 0 {
-			// for 75,47,61,53,29 if on page 47
-			// 47 must no come before 75
-			rule := day5OrderKey{page, update[i-1]}
-			if rules[rule] {
-				return false, &rule
-			}
-		}
+// rules makes a graph of the ordering rules, with an edge from each page to the pages that must come after it
+func (d *Day5) rules(input inputDay5) *graph.Graph[int] {
+	rules := graph.New[int]()
+	for _, rule := range input.orderingRules {
+		rules.AddEdge(rule[0], rule[1])
+	}
+	return rules
+}
+
+// warnCycles warns if the rule set as a whole has a cycle. That's allowed, since only the pages
+// in each update need an order, but updates with every page in the cycle can't be fixed.
+func (d *Day5) warnCycles(rules *graph.Graph[int]) {
+	if cycle, ok := rules.FindCycle(); ok {
+		fmt.Printf("%s the rules have a %v, only the pages in each update need to be in order\n\n", incorrectResultStyle.Render("warning:"), &graph.CycleError[int]{Cycle: cycle})
+	}
+}
+
+// eval checks every pair of pages in an update and returns the first rule it breaks
+func (d *Day5) eval(update []int, rules *graph.Graph[int]) (bool, *day5OrderKey) {
+	if broken := d.brokenRules(update, rules); len(broken) > 0 {
+		return false, &broken[0]
 	}
 	return true, nil
 }
 
-func (d *Day5) repair(update []int, rules map[day5OrderKey]bool) {
+// brokenRules returns every rule an update breaks
+//
+// for 75,97,47,61,53 with 97|75, 97 must come before 75
+func (d *Day5) brokenRules(update []int, rules *graph.Graph[int]) []day5OrderKey {
+	var broken []day5OrderKey
 	for i, page := range update {
-		if i < len(update)-1 {
-			// for
-			// 97|75
-			// 75,97,47,61,53
-			// 97 must come before 75, so swap them
-			rule := day5OrderKey{update[i+1], page}
-			if rules[rule] {
-				update[i], update[i+1] = update[i+1], update[i]
+		for _, later := range update[i+1:] {
+			if rules.HasEdge(later, page) {
+				broken = append(broken, day5OrderKey{later, page})
 			}
 		}
-		if i > 0 {
-			// for
-			// 29|13
-			// 61,13,29
-			// 29 comes before 13, so swap them
-			rule := day5OrderKey{page, update[i-1]}
-			if rules[rule] {
-				update[i], update[i-1] = update[i-1], update[i]
-			}
+	}
+	return broken
+}
+
+// repair orders the pages of an update by the rules between them. Pages without a rule between
+// them stay in the order they were in. It returns a *graph.CycleError if the rules for these pages
+// have a cycle.
+func (d *Day5) repair(update []int, rules *graph.Graph[int]) ([]int, error) {
+	return rules.Subgraph(update).TopoSort()
+}
+
+// writeDOT writes the rules as a Graphviz DOT graph to the DotFile option, if it's set. Rules broken
+// by an update are red.
+func (d *Day5) writeDOT(input inputDay5, rules *graph.Graph[int]) error {
+	if d.DotFile == "" {
+		return nil
+	}
+
+	broken := map[graph.Edge[int]]bool{}
+	for _, update := range input.pageUpdates {
+		for _, rule := range d.brokenRules(update, rules) {
+			broken[graph.Edge[int]{From: rule.before, To: rule.after}] = true
+		}
+	}
+
+	file, err := os.Create(d.DotFile)
+	if err != nil {
+		return fmt.Errorf("error creating dot file: %w", err)
+	}
+	defer file.Close()
+
+	err = rules.WriteDOT(file, "day5", func(e graph.Edge[int]) string {
+		if broken[e] {
+			return "color=red, penwidth=2"
 		}
+		return ""
+	})
+	if err != nil {
+		return fmt.Errorf("error writing dot file: %w", err)
 	}
+
+	fmt.Printf("wrote %d rules, %d broken, to %s\n", len(rules.Edges()), len(broken), d.DotFile)
+	return nil
 }
@@ -1,18 +1,21 @@
 package advent
 
 import (
+	"context"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sirgwain/advent-of-code-2024/advent/parse"
 	"github.com/sirgwain/advent-of-code-2024/advent/tui"
 )
 
 type Day6 struct {
-	Delay         int
-	UpdateUIMoves int
+	*Options
 }
 
 type day6Board struct {
@@ -27,6 +30,7 @@ type day6Board struct {
 }
 
 func (d *Day6) Run(part int, filename string, opts ...Option) error {
+	d.Options = newRun(opts...)
 	switch part {
 	case 1:
 		return d.part1(filename)
@@ -37,29 +41,58 @@ func (d *Day6) Run(part int, filename string, opts ...Option) error {
 	}
 }
 
-func (d *Day6) part1(filename string) error {
+// Validate reads the input file and checks its format without solving
+func (d *Day6) Validate(filename string) error {
+	_, err := d.readInput(filename)
+	return err
+}
 
+// readInput reads the lab map, which must have a guard facing up
+func (d *Day6) readInput(filename string) ([][]rune, error) {
 	input, err := readInputAsRunes(filename)
+	if err != nil {
+		return nil, err
+	}
+	guard := false
+	for y, line := range input {
+		if len(line) != len(input[0]) {
+			return nil, &parse.Error{File: filename, Line: y + 1, Text: string(line), Err: fmt.Errorf("row has %d columns, expected %d", len(line), len(input[0]))}
+		}
+		guard = guard || slices.Contains(line, '^')
+	}
+	if !guard {
+		return nil, fmt.Errorf("%s: no guard ^ on the map", filename)
+	}
+	return input, nil
+}
+
+func (d *Day6) part1(filename string) error {
+
+	input, err := d.readInput(filename)
 	if err != nil {
 		return err
 	}
 
 	x, y := findValue(input, '^')
-	board := day6Board{board: input, position: position{x: x, y: y}, direction: directionUp, vistedSquares: 1, obstaclesHit: make(map[positionDirection]int)}
+	board := day6Board{board: input, position: position{X: x, Y: y}, direction: directionUp, vistedSquares: 1, obstaclesHit: make(map[positionDirection]int)}
 
-	// create a bubbletea program
-	p := tui.NewViewportProgram(tui.NewModel("Day 6 - Part 1"))
+	// create a bubbletea program, the gate pauses the solver while we inspect the board
+	gate := tui.NewGate()
+	p := tui.NewViewportProgram(tui.NewModel("Day 6 - Part 1").
+		WithRenderMode(d.RenderMode).
+		WithInspector(inspector(board.inspect), gate))
 
-	width := len(board.board[0])
 	go func() {
+		defer gate.Done()
 		count := 0
 		board.onMove = func() {
 			// update the UI every 10th call
 			count++
 			if count > 10 {
-				p.Send(tui.UpdateViewport(board.boardView(), width))
+				p.Send(tui.UpdateBoard(&board, ""))
 				count = 0
 			}
+			gate.Step()
 		}
 		board.runBoard()
 
@@ -80,19 +113,16 @@ func (d *Day6) part1(filename string) error {
 }
 
 func (d *Day6) part2(filename string) error {
-	input, err := readInputAsRunes(filename)
+	input, err := d.readInput(filename)
 	if err != nil {
 		return err
 	}
 
 	startX, startY := findValue(input, '^')
-	board := day6Board{board: input, position: position{x: startX, y: startY}, direction: directionUp, vistedSquares: 1, obstaclesHit: make(map[positionDirection]int)}
+	board := day6Board{board: input, position: position{X: startX, Y: startY}, direction: directionUp, vistedSquares: 1, obstaclesHit: make(map[positionDirection]int)}
 	initialRun := board.duplicate()
 	initialRun.runBoard()
 
-	// create a bubbletea program
-	p := tui.NewViewportProgram(tui.NewModel("Day 6 - Part 2"))
-
 	// add an obstacle in every visited square except the start
 	obstacles := make([]position, 0, initialRun.vistedSquares-1)
 
@@ -103,49 +133,86 @@ func (d *Day6) part2(filename string) error {
 			}
 			if initialRun.board[y][x] != '.' && initialRun.board[y][x] != '#' {
 				// path, add an obstacle here
-				obstacles = append(obstacles, position{x, y})
+				obstacles = append(obstacles, position{X: x, Y: y})
 			}
 		}
 	}
 
-	width := len(board.board[0])
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// create a bubbletea program
+	p := tui.NewViewportProgram(tui.NewModel("Day 6 - Part 2").WithRenderMode(d.RenderMode))
+
+	// test each obstacle on its own board, in parallel
+	pool := NewPool(d.Workers, func(ctx context.Context, worker int, obstacle position) (day6Board, error) {
+		testBoard := board.duplicate()
+		testBoard.board[obstacle.Y][obstacle.X] = '#'
+
+		count := 0
+		testBoard.onMove = func() {
+			// update the UI every few moves
+			if d.UpdateOnNumMoves != 0 {
+				count++
+				if count > d.UpdateOnNumMoves {
+					p.Send(tui.UpdateBoard(&testBoard, ""))
+					count = 0
+				}
+			}
+
+			if d.Delay != 0 {
+				time.Sleep(time.Duration(d.Delay * int(time.Millisecond)))
+			}
+		}
+		testBoard.runBoard()
+		testBoard.onMove = nil
+
+		if testBoard.cycle {
+			testBoard.board[obstacle.Y][obstacle.X] = 'O'
+		}
+		return testBoard, nil
+	})
+
+	// count how many workers are busy for the footer
+	var busy atomic.Int32
+	pool.WithOnStatus(func(status WorkerStatus) {
+		if status.State == WorkerStarted {
+			busy.Add(1)
+		} else {
+			busy.Add(-1)
+		}
+	})
+
+	// the initial path with the obstacles that cause loops marked
+	progress := initialRun.duplicate()
 	cycleBoards := make([]day6Board, 0)
+	checked := 0
+	// the number of loops is the answer, so it's redacted while the run is recorded
+	footer := func(redact redaction) string {
+		return fmt.Sprintf("checked %d/%d obstacles, %s loops, %d/%d workers busy", checked, len(obstacles), redact.render(len(cycleBoards), unknownSolution), busy.Load(), pool.Workers())
+	}
+	done := make(chan struct{})
 	go func() {
-		for _, obstacle := range obstacles {
-			testBoard := board.duplicate()
-			testBoard.board[obstacle.y][obstacle.x] = '#'
-
-			done := make(chan struct{})
-			// Start the goroutine
-			go func() {
-				count := 0
-				testBoard.onMove = func() {
-					// update the UI every 10th call
-					if d.UpdateUIMoves != 0 {
-						count++
-						if count > d.UpdateUIMoves {
-							p.Send(tui.UpdateViewport(testBoard.boardView(), width))
-							count = 0
-						}
-					}
-
-					if d.Delay != 0 {
-						time.Sleep(time.Duration(d.Delay * int(time.Millisecond)))
-					}
-				}
-				testBoard.runBoard()
-				close(done)
-			}()
-			// Wait for run to finish
-			<-done
-
-			if testBoard.cycle {
-				testBoard.board[obstacle.y][obstacle.x] = 'O'
-				cycleBoards = append(cycleBoards, testBoard)
+		defer close(done)
+		for result := range pool.Stream(ctx, obstacles) {
+			if result.Err != nil {
+				return
+			}
+			checked++
+			if result.Value.cycle {
+				cycleBoards = append(cycleBoards, result.Value)
+				progress.board[result.Job.Y][result.Job.X] = 'O'
+			}
+			if d.UpdateOnNumMoves == 0 && (result.Value.cycle || checked%100 == 0 || checked == len(obstacles)) {
+				p.Send(tui.UpdateBoard(&progress, footer(d.redaction())))
 			}
 		}
 
-		// signal we are done
+		// wait for the user to reveal the solution, or signal we are done
+		if d.RedactSolution {
+			p.Send(tui.Reveal(tui.UpdateBoard(&progress, footer(noRedaction))))
+			return
+		}
 		p.Send(tea.Quit())
 	}()
 
@@ -153,7 +220,11 @@ func (d *Day6) part2(filename string) error {
 		return fmt.Errorf("could not start program: %v", err)
 	}
 
-	if d.UpdateUIMoves != 0 {
+	// stop the workers if we quit early
+	cancel()
+	<-done
+
+	if d.UpdateOnNumMoves != 0 {
 		for _, b := range cycleBoards {
 			fmt.Printf("%s\n", b.boardView())
 		}
@@ -187,37 +258,21 @@ func (b *day6Board) runBoard() {
 }
 
 func (b *day6Board) moveGuard() {
-	var x, y int
-
-	switch b.direction {
-	case directionUp:
-		y = -1
-	case directionRight:
-		x = 1
-	case directionDown:
-		y = 1
-	case directionLeft:
-		x = -1
-	}
-
-	nextPosition := position{
-		x: b.x + x,
-		y: b.y + y,
-	}
+	nextPosition := b.position.Move(b.direction)
 
-	if nextPosition.y < 0 || nextPosition.y == len(b.board) {
+	if nextPosition.Y < 0 || nextPosition.Y == len(b.board) {
 		// at edge, no more moves
 		b.complete = true
 		return
 	}
 
-	if nextPosition.x < 0 || nextPosition.x == len(b.board[b.y]) {
+	if nextPosition.X < 0 || nextPosition.X == len(b.board[b.Y]) {
 		// at edge, no more moves
 		b.complete = true
 		return
 	}
 
-	nextSquare := getBoardValue(nextPosition.x, nextPosition.y, b.board)
+	nextSquare := getBoardValue(nextPosition.X, nextPosition.Y, b.board)
 
 	if nextSquare == '#' {
 		// obstacle, turn
@@ -231,7 +286,7 @@ func (b *day6Board) moveGuard() {
 			return
 		}
 		b.obstaclesHit[key]++
-		b.direction = b.direction.turnRight()
+		b.direction = b.direction.TurnRight()
 		return
 	}
 
@@ -241,37 +296,47 @@ func (b *day6Board) moveGuard() {
 	}
 
 	// move the guardq
-	b.board[b.y][b.x] = 'X'
-	b.y = b.y + y
-	b.x = b.x + x
-	b.board[b.y][b.x] = b.direction.getChar()
+	b.board[b.Y][b.X] = 'X'
+	b.position = nextPosition
+	b.board[b.Y][b.X] = b.direction.Char()
 }
 
-// prerender some styled characters
-var (
-	renderedPath     = pathStyle.Render("X")
-	renderedObstacle = obstacleStyle.Render("#")
-)
-
 func (b *day6Board) boardView() string {
-	guard := b.direction.getChar()
-	renderedGuard := guardStyle.Render(string(guard))
+	return day6Renderer.RenderRows(b.board)
+}
+
+// Size and Cell make the board a tui.Board so large boards can be compacted in the viewport
+// Focus keeps the guard centered when following
+func (b *day6Board) Size() (width, height int) {
+	return len(b.board[0]), len(b.board)
+}
+
+func (b *day6Board) Focus() (x, y int) {
+	return b.X, b.Y
+}
 
+func (b *day6Board) Cell(x, y int) tui.Cell {
+	return day6Renderer.Cell(position{X: x, Y: y}, b.board[y][x])
+}
+
+// inspect describes a square and which directions the guard has hit it from
+func (b *day6Board) inspect(p position) string {
 	var sb strings.Builder
-	for _, line := range b.board {
-		for _, r := range line {
-			switch r {
-			case 'X':
-				sb.WriteString(renderedPath)
-			case '#':
-				sb.WriteString(renderedObstacle)
-			case guard:
-				sb.WriteString(renderedGuard)
-			default:
-				sb.WriteRune(r)
-			}
+	switch r := b.board[p.Y][p.X]; {
+	case p == b.position:
+		fmt.Fprintf(&sb, "guard facing %s\n", string(b.direction.Char()))
+	case r == 'X':
+		sb.WriteString("visited\n")
+	case r == '#':
+		sb.WriteString("obstacle\n")
+	default:
+		sb.WriteString("empty\n")
+	}
+
+	for _, dir := range cardinalDirections {
+		if hits := b.obstaclesHit[positionDirection{position: p, direction: dir}]; hits > 0 {
+			fmt.Fprintf(&sb, "hit moving %s: %d\n", string(dir.Char()), hits)
 		}
-		sb.WriteString("\n")
 	}
 	return sb.String()
 }
@@ -1,17 +1,24 @@
 package advent
 
 import (
-	"bufio"
+	"context"
 	"fmt"
-	"os"
+	"math/big"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sirgwain/advent-of-code-2024/advent/combin"
+	"github.com/sirgwain/advent-of-code-2024/advent/mathx"
+	"github.com/sirgwain/advent-of-code-2024/advent/parse"
 	"github.com/sirgwain/advent-of-code-2024/advent/tui"
 )
 
 type Day7 struct {
+	*Options
 }
 
 type day7Equation struct {
@@ -30,6 +37,7 @@ const (
 )
 
 func (d *Day7) Run(part int, filename string, opts ...Option) error {
+	d.Options = newRun(opts...)
 	switch part {
 	case 1:
 		return d.part1(filename)
@@ -40,45 +48,32 @@ func (d *Day7) Run(part int, filename string, opts ...Option) error {
 	}
 }
 
+// Validate reads the input file and checks its format without solving
+func (d *Day7) Validate(filename string) error {
+	_, err := d.readInput(filename)
+	return err
+}
+
+var day7Line = regexp.MustCompile(`^\d+:( \d+)+$`)
+
+// readInput reads one equation per line, like 190: 10 19
 func (d *Day7) readInput(filename string) ([]day7Equation, error) {
-	file, err := os.Open(filename)
+	file, err := parse.ReadFile(filename)
 	if err != nil {
-		return nil, fmt.Errorf("error opening file: %w", err)
+		return nil, err
 	}
-	defer file.Close()
 
 	var input []day7Equation
-
-	scanner := bufio.NewScanner(file)
-	index := 0
-	for scanner.Scan() {
-		line := scanner.Text()
-		resultSplit := strings.Split(line, ":")
-		if len(resultSplit) != 2 {
-			return nil, fmt.Errorf("line doesn't contain two numbers: %s", line)
+	for index, line := range file.Lines {
+		if err := line.Expect(day7Line); err != nil {
+			return nil, err
 		}
-
-		var err error
-		result, err := strconv.Atoi(resultSplit[0])
+		nums, err := line.Ints()
 		if err != nil {
-			return nil, fmt.Errorf("result is not a number: %s %v", resultSplit[0], err)
-		}
-
-		valueSplit := strings.Split(strings.TrimSpace(resultSplit[1]), " ")
-		values := make([]int, len(valueSplit))
-		for i, v := range valueSplit {
-			values[i], err = strconv.Atoi(v)
-			if err != nil {
-				return nil, fmt.Errorf("value is not a number line: %s value: %s %v", line, v, err)
-			}
+			return nil, err
 		}
 
-		input = append(input, day7Equation{num: index, result: result, values: values})
-		index++
-	}
-
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("error reading file: %w", err)
+		input = append(input, day7Equation{num: index, result: nums[0], values: nums[1:]})
 	}
 
 	return input, nil
@@ -94,34 +89,40 @@ func (d *Day7) part1(filename string) error {
 	for i := range equations {
 		eq := &equations[i]
 		numValues := len(eq.values)
-		combos := generateCombinations([]operator{operatorAdd, operatorMul}, numValues-1)
 
-		for _, operators := range combos {
-			result := d.eval(eq.values[0], eq.values[1], operators[0])
-			for i := 2; i < numValues; i++ {
-				result = d.eval(result, eq.values[i], operators[i-1])
+		for operators := range combin.Product([]operator{operatorAdd, operatorMul}, numValues-1) {
+			result, ok := d.evaluate(eq.values, operators)
+			if !ok {
+				fmt.Printf("%s\n", eq.viewOverflow(operators))
+				continue
 			}
 
 			fmt.Printf("%s\n", eq.view(operators, result))
 
 			if result == eq.result {
-				eq.solution = operators
+				eq.solution = slices.Clone(operators)
 				break
 			}
 		}
 	}
 
-	sum := 0
+	var sum mathx.Sum
+	if d.BigInt {
+		sum.UseBig()
+	}
 	count := 0
 	for _, eq := range equations {
 		if eq.solution != nil {
-			sum += eq.result
+			sum.Add(eq.result)
 			count++
 		}
 	}
 
 	// output the result
-	fmt.Printf("Valid Tests: %s, Sum of Test Values: %s\n", correctResultStyle.Render(strconv.Itoa(count)), solutionStyle.Render(strconv.Itoa(sum)))
+	if sum.IsBig() && !d.BigInt {
+		fmt.Println(overflowWarning("the sum of test values"))
+	}
+	fmt.Printf("Valid Tests: %s, Sum of Test Values: %s\n", correctResultStyle.Render(strconv.Itoa(count)), solutionStyle.Render(sum.String()))
 
 	// Output the dist
 	return nil
@@ -133,81 +134,136 @@ func (d *Day7) part2(filename string) error {
 		return err
 	}
 
-	numWorkers := 19
-
-	// create a bubbletea program
-	p := tui.NewViewportProgram(tui.NewModel("Day 7 - Part 2").WithViewport(make([]string, numWorkers+1)))
-
-	jobs := make(chan *day7Equation, len(equations))    // Channel to queue jobs
-	results := make(chan *day7Equation, len(equations)) // Channel to collect results
-
-	// Worker function
-	worker := func(id int, equations <-chan *day7Equation, results chan<- *day7Equation) {
-		for eq := range equations {
-			time.Sleep(50 * time.Millisecond)
-			numValues := len(eq.values)
-			combos := generateCombinations([]operator{operatorAdd, operatorMul, operatorCat}, numValues-1)
-
-			var solution []operator
-			var solutionResult int
-			for _, operators := range combos {
-				result := d.eval(eq.values[0], eq.values[1], operators[0])
-				for i := 2; i < numValues; i++ {
-					result = d.eval(result, eq.values[i], operators[i-1])
-				}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-				solution = operators
-				solutionResult = result
-
-				if result == eq.result {
-					eq.solution = operators
-					break
-				}
-			}
-			p.Send(tui.UpdateViewportLine(id, fmt.Sprintf("%d: %s", eq.num, eq.view(solution, solutionResult))))
-			results <- eq
+	// the workers send their progress to the program, which is sized by the number of workers
+	var p *tea.Program
+	ops := []operator{operatorAdd, operatorMul, operatorCat}
+	pool := NewPool(d.Workers, func(ctx context.Context, worker int, eq *day7Equation) (*day7Equation, error) {
+		if d.Delay != 0 {
+			time.Sleep(time.Duration(d.Delay) * time.Millisecond)
 		}
-	}
+		operators, result, ok := d.solve(eq, ops)
+		if ok {
+			eq.solution = operators
+		}
+		p.Send(tui.UpdateViewportLine(worker, fmt.Sprintf("%d: %s", eq.num, eq.view(operators, result))))
+		return eq, nil
+	})
+	numWorkers := pool.Workers()
 
-	sum := 0
-	count := 0
+	// create a bubbletea program, one line per worker and one for the sum
+	p = tui.NewViewportProgram(tui.NewModel("Day 7 - Part 2").WithViewport(make([]string, numWorkers+1)))
 
-	// Start the workers
-	for i := 0; i < numWorkers; i++ {
-		go worker(i, jobs, results)
+	jobs := make([]*day7Equation, len(equations))
+	for i := range equations {
+		jobs[i] = &equations[i]
 	}
 
-	// send all the jobs to the workers
-	for i := 0; i < len(equations); i++ {
-		jobs <- &equations[i]
+	var sum mathx.Sum
+	if d.BigInt {
+		sum.UseBig()
 	}
-	close(jobs)
+	count := 0
 
 	// update the ui as jobs come in
+	done := make(chan struct{})
 	go func() {
-		for a := 0; a < len(equations); a++ {
-			result := <-results
-			if result.solution != nil {
+		defer close(done)
+		for result := range pool.Stream(ctx, jobs) {
+			if result.Err != nil {
+				return
+			}
+			if result.Value.solution != nil {
 				count++
-				sum += result.result
-				// p.Send(updateViewportLine{lineNum: numWorkers, line: fmt.Sprintf("Valid Tests: %s, Sum of Test Values: %s", correctResultStyle.Render(strconv.Itoa(count)), solutionStyle.Render(strconv.Itoa(sum)))})
-				p.Send(tui.UpdateViewportLine(numWorkers, fmt.Sprintf("Valid Tests: %s, Sum of Test Values: %s", correctResultStyle.Render(strconv.Itoa(count)), solutionStyle.Render("<redacted>"))))
+				sum.Add(result.Value.result)
+				// we don't know the sum until all the equations are checked
+				p.Send(tui.UpdateViewportLine(numWorkers, fmt.Sprintf("Valid Tests: %s, Sum of Test Values: %s", correctResultStyle.Render(strconv.Itoa(count)), d.redaction().renderText(sum.String()))))
 			}
 		}
+		if d.RedactSolution {
+			p.Send(tui.Reveal(tui.UpdateViewportLine(numWorkers, fmt.Sprintf("Valid Tests: %s, Sum of Test Values: %s", correctResultStyle.Render(strconv.Itoa(count)), noRedaction.renderText(sum.String())))))
+		}
 	}()
 
 	if _, err := p.Run(); err != nil {
 		return fmt.Errorf("could not start program: %v", err)
 	}
 
+	// stop the workers if we quit early
+	cancel()
+	<-done
+
 	// output the result
-	fmt.Printf("Valid Tests: %s, Sum of Test Values: %s\n", correctResultStyle.Render(strconv.Itoa(count)), solutionStyle.Render(strconv.Itoa(sum)))
+	if sum.IsBig() && !d.BigInt {
+		fmt.Println(overflowWarning("the sum of test values"))
+	}
+	fmt.Printf("Valid Tests: %s, Sum of Test Values: %s\n", correctResultStyle.Render(strconv.Itoa(count)), solutionStyle.Render(sum.String()))
 
 	// Output the dist
 	return nil
 }
 
+// solve tries combinations of operators on an equation until one works. It returns the solution, or the
+// last combination tried and false if there isn't one.
+func (d *Day7) solve(eq *day7Equation, ops []operator) ([]operator, int, bool) {
+	numValues := len(eq.values)
+
+	// partials[i] is the result of the first i+1 values with the operators so far,
+	// overflowed[i] is true if it got too big for an int along the way
+	partials := make([]int, numValues)
+	overflowed := make([]bool, numValues)
+	partials[0] = eq.values[0]
+
+	// every operator makes the result bigger unless there is a 0, so stop
+	// trying combinations as soon as the result is too big
+	canPrune := !slices.Contains(eq.values, 0)
+	keep := func(prefix []operator) bool {
+		i := len(prefix)
+		var ok bool
+		partials[i], ok = d.eval(partials[i-1], eq.values[i], prefix[i-1])
+		overflowed[i] = overflowed[i-1] || !ok
+		if canPrune {
+			return !overflowed[i] && partials[i] <= eq.result
+		}
+		return true
+	}
+
+	var tried []operator
+	var triedResult int
+	for operators := range combin.ProductFunc(ops, numValues-1, keep) {
+		tried = operators
+		triedResult = partials[numValues-1]
+		if overflowed[numValues-1] {
+			// a 0 can bring an overflowed result back down, so check it with big integers
+			result, ok := d.evaluate(eq.values, operators)
+			if !ok {
+				continue
+			}
+			triedResult = result
+		}
+
+		if triedResult == eq.result {
+			return slices.Clone(operators), triedResult, true
+		}
+	}
+	return slices.Clone(tried), triedResult, false
+}
+
 func (eq *day7Equation) view(solution []operator, result int) string {
+	if result == eq.result {
+		return eq.viewOperators(solution) + " = " + correctResultStyle.Render(strconv.Itoa(result))
+	}
+	return eq.viewOperators(solution) + " = " + incorrectResultStyle.Render(strconv.Itoa(result))
+}
+
+// viewOverflow shows operators whose result is too big for an int
+func (eq *day7Equation) viewOverflow(solution []operator) string {
+	return eq.viewOperators(solution) + " = " + incorrectResultStyle.Render("overflow")
+}
+
+func (eq *day7Equation) viewOperators(solution []operator) string {
 	var sb strings.Builder
 
 	sb.WriteString(numberStyle.Render(strconv.Itoa(eq.values[0])))
@@ -228,48 +284,55 @@ func (eq *day7Equation) view(solution []operator, result int) string {
 		sb.WriteString(numberStyle.Render(strconv.Itoa(eq.values[i])))
 	}
 
-	if result == eq.result {
-		sb.WriteString(" = " + correctResultStyle.Render(strconv.Itoa(result)))
-	} else {
-		sb.WriteString(" = " + incorrectResultStyle.Render(strconv.Itoa(result)))
-	}
-
 	return sb.String()
 }
 
-// generateCombinations generates all combinations of operators for a given size
-func generateCombinations(ops []operator, size int) [][]operator {
-	if size == 0 {
-		return [][]operator{{}}
-	}
-
-	smallerCombos := generateCombinations(ops, size-1) // Recursive step
-	var result [][]operator
-
-	for _, combo := range smallerCombos {
-		for _, op := range ops {
-			newCombo := append([]operator{}, combo...) // Copy current combination
-			newCombo = append(newCombo, op)            // Add the new operator
-			result = append(result, newCombo)
-		}
+// eval applies an operator, returning false if the result overflows an int
+func (d *Day7) eval(num1, num2 int, op operator) (int, bool) {
+	switch op {
+	case operatorAdd:
+		return mathx.AddChecked(num1, num2)
+	case operatorMul:
+		return mathx.MulChecked(num1, num2)
+	case operatorCat:
+		return mathx.ConcatChecked(num1, num2)
 	}
 
-	return result
+	panic("unknown operator")
 }
 
-func (d *Day7) eval(num1, num2 int, op operator) int {
+// evalBig applies an operator with big integers
+func (d *Day7) evalBig(num1, num2 *big.Int, op operator) *big.Int {
 	switch op {
 	case operatorAdd:
-		return num1 + num2
+		return new(big.Int).Add(num1, num2)
 	case operatorMul:
-		return num1 * num2
+		return new(big.Int).Mul(num1, num2)
 	case operatorCat:
-		cat, err := strconv.Atoi(strconv.Itoa(num1) + strconv.Itoa(num2))
-		if err != nil {
-			panic(fmt.Sprintf("can't cat %d %d", num1, num2))
-		}
-		return cat
+		return mathx.ConcatBig(num1, num2)
 	}
 
 	panic("unknown operator")
 }
+
+// evaluate applies the operators to the values left to right. If an int overflows along the way it
+// switches to big integers. ok is false if the final result doesn't fit in an int.
+func (d *Day7) evaluate(values []int, operators []operator) (int, bool) {
+	result := values[0]
+	for i := 1; i < len(values); i++ {
+		var ok bool
+		if result, ok = d.eval(result, values[i], operators[i-1]); ok {
+			continue
+		}
+
+		bigResult := big.NewInt(int64(values[0]))
+		for j := 1; j < len(values); j++ {
+			bigResult = d.evalBig(bigResult, big.NewInt(int64(values[j])), operators[j-1])
+		}
+		if !bigResult.IsInt64() {
+			return 0, false
+		}
+		return int(bigResult.Int64()), true
+	}
+	return result, true
+}
@@ -3,7 +3,6 @@ package advent
 import (
 	"fmt"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/sirgwain/advent-of-code-2024/advent/tui"
@@ -14,9 +13,9 @@ type Day8 struct {
 }
 
 type day8Board struct {
-	board           [][]rune
+	board           *Grid[rune]
 	antennas        map[rune][]position
-	antinodes       map[position]bool
+	antinodes       *SparseGrid[bool]
 	onAntinodeFound func(p position)
 	solution        int
 }
@@ -38,6 +37,12 @@ func (d *Day8) Run(part int, filename string, opts ...Option) error {
 	}
 }
 
+// Validate reads the input file and checks its format without solving
+func (d *Day8) Validate(filename string) error {
+	_, err := d.readInput(filename)
+	return err
+}
+
 func (d *Day8) RunVisual(part int, filename string, opts ...Option) error {
 	d.Options = newRun(opts...)
 	input, err := d.readInput(filename)
@@ -53,55 +58,56 @@ func (d *Day8) RunVisual(part int, filename string, opts ...Option) error {
 	}
 }
 
-func (d *Day8) readInput(filename string) ([][]rune, error) {
-	return readInputAsRunes(filename)
+func (d *Day8) readInput(filename string) (*Grid[rune], error) {
+	return readInputAsRuneGrid(filename)
 }
 
-func (d *Day8) part1(input [][]rune) error {
+func (d *Day8) part1(input *Grid[rune]) error {
 
 	board := day8Board{board: input}
 	board.findAntinodes()
 
 	fmt.Printf("%s\n", board.view())
-	fmt.Printf("Antenna Types: %s, valid Antinodes: %s\n",
-		correctResultStyle.Render(strconv.Itoa(len(board.antennas))),
-		solutionStyle.Render(strconv.Itoa(len(board.antinodes))))
+	fmt.Print(board.viewSolution(noRedaction))
 
 	return nil
 }
 
-func (d *Day8) part2(input [][]rune) error {
+func (d *Day8) part2(input *Grid[rune]) error {
 	board := day8Board{board: input}
 	board.findAntinodesWithResonance()
 
 	// fmt.Printf("%s\n", board.view())
 	fmt.Printf("Antenna Types: %s, valid Antinodes: %s\n",
 		correctResultStyle.Render(strconv.Itoa(len(board.antennas))),
-		solutionStyle.Render(strconv.Itoa(len(board.antinodes))))
+		solutionStyle.Render(strconv.Itoa(board.antinodes.Len())))
 
 	return nil
 }
 
-func (d *Day8) part2Visual(input [][]rune) error {
+func (d *Day8) part2Visual(input *Grid[rune]) error {
 	// create a bubbletea program
 	p := tui.NewViewportProgram(tui.NewModel("Day 8 - Part 2"))
 
 	// find the solution so we can hide it from the output
-	silentBoard := day8Board{board: duplicate2DSlice(input)}
+	silentBoard := day8Board{board: input.Clone()}
 	silentBoard.findAntinodesWithResonance()
 
-	board := day8Board{board: input, solution: len(silentBoard.antinodes)}
-	width := len(board.board[0])
+	board := day8Board{board: input, solution: silentBoard.antinodes.Len()}
+	width := board.board.Width()
 	go func() {
 		// update the UI
 		board.onAntinodeFound = func(pos position) {
-			content := fmt.Sprintf("%s\n%s", board.view(), board.viewSolution(d.RedactSolution))
+			content := fmt.Sprintf("%s\n%s", board.view(), board.viewSolution(d.redaction()))
 			p.Send(tui.UpdateViewport(content, width))
 			if d.Delay != 0 {
 				time.Sleep(time.Millisecond * time.Duration(d.Delay))
 			}
 		}
 		board.findAntinodesWithResonance()
+		if d.RedactSolution {
+			p.Send(tui.Reveal(tui.UpdateViewport(fmt.Sprintf("%s\n%s", board.view(), board.viewSolution(noRedaction)), width)))
+		}
 	}()
 
 	if _, err := p.Run(); err != nil {
@@ -109,9 +115,7 @@ func (d *Day8) part2Visual(input [][]rune) error {
 	}
 
 	fmt.Printf("%s\n", board.view())
-	fmt.Printf("Antenna Types: %s, valid Antinodes: %s\n",
-		correctResultStyle.Render(strconv.Itoa(len(board.antennas))),
-		solutionStyle.Render(strconv.Itoa(len(board.antinodes))))
+	fmt.Print(board.viewSolution(noRedaction))
 
 	return nil
 }
@@ -131,25 +135,23 @@ func (d *Day8) part2Visual(input [][]rune) error {
 // a1 = p2+(-1,-2)*2
 // a2 = p1-(-1,-2)*2
 func findAntinodePositions(p1 position, p2 position) (a1 position, a2 position) {
-	xdist := p1.x - p2.x
-	ydist := p1.y - p2.y
+	dist := p1.Sub(p2)
 
-	a1 = position{p2.x + xdist*2, p2.y + ydist*2}
-	a2 = position{p1.x - xdist*2, p1.y - ydist*2}
+	a1 = p2.Add(dist.Scale(2))
+	a2 = p1.Sub(dist.Scale(2))
 
 	return a1, a2
 }
 
-func findAntinodeLinePoints(p1 position, p2 position, width, height int) []position {
-	xdist := p1.x - p2.x
-	ydist := p1.y - p2.y
+func findAntinodeLinePoints[T comparable](p1 position, p2 position, board *Grid[T]) []position {
+	dist := p1.Sub(p2)
 
 	linePoints := []position{p1, p2}
 	// do the y=mx+b forward
 	pos := p1
 	for {
-		pos = position{pos.x + xdist, pos.y + ydist}
-		if !validPosition(pos, width, height) {
+		pos = pos.Add(dist)
+		if !board.InBounds(pos) {
 			break
 		}
 		linePoints = append(linePoints, pos)
@@ -158,8 +160,8 @@ func findAntinodeLinePoints(p1 position, p2 position, width, height int) []posit
 	// now backward
 	pos = p2
 	for {
-		pos = position{pos.x - xdist, pos.y - ydist}
-		if !validPosition(pos, width, height) {
+		pos = pos.Sub(dist)
+		if !board.InBounds(pos) {
 			break
 		}
 		linePoints = append(linePoints, pos)
@@ -169,37 +171,31 @@ func findAntinodeLinePoints(p1 position, p2 position, width, height int) []posit
 }
 
 func (b *day8Board) findAntinodes() {
-	height, width := len(b.board), len(b.board[0])
-
 	antennas := make(map[rune][]position)
-	antinodes := make(map[position]bool)
-
-	for y := 0; y < height; y++ {
-		for x := 0; x < width; x++ {
-			antenna := b.board[y][x]
-			if antenna == '.' {
-				continue
-			}
-			// found an antenna
-			p1 := position{x, y}
-			for _, p2 := range antennas[antenna] {
-				a1, a2 := findAntinodePositions(p1, p2)
-				if validPosition(a1, width, height) {
-					antinodes[a1] = true
-					if b.onAntinodeFound != nil {
-						b.onAntinodeFound(a1)
-					}
+	antinodes := NewSparseGrid[bool]()
 
+	for p1, antenna := range b.board.All() {
+		if antenna == '.' {
+			continue
+		}
+		// found an antenna
+		for _, p2 := range antennas[antenna] {
+			a1, a2 := findAntinodePositions(p1, p2)
+			if b.board.InBounds(a1) {
+				antinodes.Set(a1, true)
+				if b.onAntinodeFound != nil {
+					b.onAntinodeFound(a1)
 				}
-				if validPosition(a2, width, height) {
-					antinodes[a2] = true
-					if b.onAntinodeFound != nil {
-						b.onAntinodeFound(a2)
-					}
+
+			}
+			if b.board.InBounds(a2) {
+				antinodes.Set(a2, true)
+				if b.onAntinodeFound != nil {
+					b.onAntinodeFound(a2)
 				}
 			}
-			antennas[antenna] = append(antennas[antenna], p1)
 		}
+		antennas[antenna] = append(antennas[antenna], p1)
 	}
 
 	b.antennas = antennas
@@ -207,72 +203,33 @@ func (b *day8Board) findAntinodes() {
 }
 
 func (b *day8Board) findAntinodesWithResonance() {
-	height, width := len(b.board), len(b.board[0])
-
 	b.antennas = make(map[rune][]position)
-	b.antinodes = make(map[position]bool)
+	b.antinodes = NewSparseGrid[bool]()
 
-	for y := 0; y < height; y++ {
-		for x := 0; x < width; x++ {
-			antenna := b.board[y][x]
-			if antenna == '.' {
-				continue
-			}
-			// found an antenna
-			p1 := position{x, y}
-			for _, p2 := range b.antennas[antenna] {
-				points := findAntinodeLinePoints(p1, p2, width, height)
-				for _, point := range points {
-					b.antinodes[point] = true
-					if b.onAntinodeFound != nil {
-						b.onAntinodeFound(point)
-					}
+	for p1, antenna := range b.board.All() {
+		if antenna == '.' {
+			continue
+		}
+		// found an antenna
+		for _, p2 := range b.antennas[antenna] {
+			points := findAntinodeLinePoints(p1, p2, b.board)
+			for _, point := range points {
+				b.antinodes.Set(point, true)
+				if b.onAntinodeFound != nil {
+					b.onAntinodeFound(point)
 				}
 			}
-			b.antennas[antenna] = append(b.antennas[antenna], p1)
 		}
+		b.antennas[antenna] = append(b.antennas[antenna], p1)
 	}
 }
 
-// prerender some styled characters
-var (
-	renderedAntinode = antinodeStyle.Render("#")
-)
-
 func (b *day8Board) view() string {
-	var sb strings.Builder
-	for y, line := range b.board {
-		for x, r := range line {
-			switch r {
-			case '.':
-				// if there is an antinode here, render it
-				if b.antinodes[position{x, y}] {
-					sb.WriteString(renderedAntinode)
-				} else {
-					sb.WriteRune(r)
-				}
-			default:
-				if b.antinodes[position{x, y}] {
-					sb.WriteString(antennaWithAntinodeStyle.Render(string(r)))
-				} else {
-					sb.WriteString(antennaStyle.Render(string(r)))
-				}
-			}
-		}
-		sb.WriteString("\n")
-	}
-	return sb.String()
+	return day8Renderer.Render(b.board, day8AntinodeRenderer.Over(b.antinodes.Get))
 }
 
-func (b *day8Board) viewSolution(redactSolution bool) string {
-	// hide the solution if it's within 100
-	solution := solutionStyle.Render(strconv.Itoa(len(b.antinodes)))
-	if redactSolution && b.solution-len(b.antinodes) < 100 {
-		solution = solutionStyle.Render("<redacted>")
-	}
-
+func (b *day8Board) viewSolution(redact redaction) string {
 	return fmt.Sprintf("Antenna Types: %s, valid Antinodes: %s\n",
 		correctResultStyle.Render(strconv.Itoa(len(b.antennas))),
-		solution)
-
+		redact.render(b.antinodes.Len(), b.solution))
 }
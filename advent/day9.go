@@ -2,10 +2,10 @@ package advent
 
 import (
 	"fmt"
-	"io"
-	"os"
 	"strconv"
 	"strings"
+
+	"github.com/sirgwain/advent-of-code-2024/advent/parse"
 )
 
 type Day9 struct {
@@ -29,25 +29,34 @@ func (d *Day9) Run(part int, filename string, opts ...Option) error {
 	}
 }
 
+// Validate reads the input file and checks its format without solving
+func (d *Day9) Validate(filename string) error {
+	_, err := d.readInput(filename)
+	return err
+}
+
+// readInput reads the disk map, a single line of digits
 func (d *Day9) readInput(filename string) ([]int, error) {
-	file, err := os.Open(filename)
+	file, err := parse.ReadFile(filename)
 	if err != nil {
-		return nil, fmt.Errorf("error opening file: %w", err)
+		return nil, err
 	}
-	defer file.Close()
 
-	content, err := io.ReadAll(file)
+	sections, err := file.SectionsN(1)
 	if err != nil {
-		return nil, fmt.Errorf("error reading file: %w", err)
+		return nil, err
+	}
+	if len(sections[0]) != 1 {
+		return nil, sections[0][1].Errorf(0, "disk map should be a single line")
 	}
 
-	input := make([]int, len(content))
-	for i, c := range content {
-		num, err := strconv.Atoi(string(c))
-		if err != nil {
-			return nil, fmt.Errorf("character is not an int: %v %w", c, err)
+	line := sections[0][0]
+	input := make([]int, len(line.Text))
+	for i, c := range line.Text {
+		if c < '0' || c > '9' {
+			return nil, line.Errorf(i+1, "%q is not a digit", c)
 		}
-		input[i] = num
+		input[i] = int(c - '0')
 	}
 
 	return input, nil
@@ -0,0 +1,175 @@
+package geom
+
+import (
+	"fmt"
+)
+
+// Direction is one of the eight directions on a board. The cardinal directions
+// come first, clockwise, followed by the diagonals, clockwise
+type Direction int
+
+const (
+	Up Direction = iota
+	Right
+	Down
+	Left
+	UpRight
+	DownRight
+	DownLeft
+	UpLeft
+)
+
+// Cardinal are the four directions without diagonals, clockwise from Up
+var Cardinal = []Direction{Up, Right, Down, Left}
+
+// All are the cardinal directions followed by the diagonals
+var All = []Direction{Up, Right, Down, Left, UpRight, DownRight, DownLeft, UpLeft}
+
+// the directions going clockwise in 45 degree steps, for rotating
+var clockwise = [8]Direction{Up, UpRight, Right, DownRight, Down, DownLeft, Left, UpLeft}
+
+// the index of each direction in clockwise
+var clockwiseIndex = [8]int{
+	Up:        0,
+	UpRight:   1,
+	Right:     2,
+	DownRight: 3,
+	Down:      4,
+	DownLeft:  5,
+	Left:      6,
+	UpLeft:    7,
+}
+
+var offsets = [8]Point{
+	Up:        {0, -1},
+	Right:     {1, 0},
+	Down:      {0, 1},
+	Left:      {-1, 0},
+	UpRight:   {1, -1},
+	DownRight: {1, 1},
+	DownLeft:  {-1, 1},
+	UpLeft:    {-1, -1},
+}
+
+var chars = [8]rune{
+	Up:        '^',
+	Right:     '>',
+	Down:      'v',
+	Left:      '<',
+	UpRight:   '↗',
+	DownRight: '↘',
+	DownLeft:  '↙',
+	UpLeft:    '↖',
+}
+
+var names = [8]string{
+	Up:        "up",
+	Right:     "right",
+	Down:      "down",
+	Left:      "left",
+	UpRight:   "up-right",
+	DownRight: "down-right",
+	DownLeft:  "down-left",
+	UpLeft:    "up-left",
+}
+
+// ParseDirection parses an arrow (^>v< and diagonal arrows), UDLR or NSEW
+func ParseDirection(c rune) (Direction, error) {
+	switch c {
+	case '^', 'U', 'N':
+		return Up, nil
+	case '>', 'R', 'E':
+		return Right, nil
+	case 'v', 'D', 'S':
+		return Down, nil
+	case '<', 'L', 'W':
+		return Left, nil
+	case '↗':
+		return UpRight, nil
+	case '↘':
+		return DownRight, nil
+	case '↙':
+		return DownLeft, nil
+	case '↖':
+		return UpLeft, nil
+	}
+	return Up, fmt.Errorf("%q is not a direction", c)
+}
+
+func (d Direction) valid() bool {
+	return d >= Up && d <= UpLeft
+}
+
+// Offset is the point one step in this direction from 0,0
+func (d Direction) Offset() Point {
+	if !d.valid() {
+		return Point{}
+	}
+	return offsets[d]
+}
+
+// Rotate45 turns clockwise by n 45 degree steps, or counterclockwise if n is negative
+func (d Direction) Rotate45(n int) Direction {
+	if !d.valid() {
+		return d
+	}
+	return clockwise[((clockwiseIndex[d]+n)%8+8)%8]
+}
+
+// TurnRight turns 90 degrees clockwise
+func (d Direction) TurnRight() Direction {
+	return d.Rotate45(2)
+}
+
+// TurnLeft turns 90 degrees counterclockwise
+func (d Direction) TurnLeft() Direction {
+	return d.Rotate45(-2)
+}
+
+// TurnAround turns 180 degrees
+func (d Direction) TurnAround() Direction {
+	return d.Rotate45(4)
+}
+
+// Char returns the arrow for this direction
+func (d Direction) Char() rune {
+	if !d.valid() {
+		return ' '
+	}
+	return chars[d]
+}
+
+// String returns the name of the direction, like up or down-left
+func (d Direction) String() string {
+	if !d.valid() {
+		return fmt.Sprintf("Direction(%d)", int(d))
+	}
+	return names[d]
+}
+
+// MarshalText formats the direction by name
+func (d Direction) MarshalText() ([]byte, error) {
+	if !d.valid() {
+		return nil, fmt.Errorf("invalid direction %d", int(d))
+	}
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText parses a direction name or a single direction character
+func (d *Direction) UnmarshalText(text []byte) error {
+	for dir, name := range names {
+		if string(text) == name {
+			*d = Direction(dir)
+			return nil
+		}
+	}
+	if r := []rune(string(text)); len(r) == 1 {
+		dir, err := ParseDirection(r[0])
+		if err != nil {
+			return err
+		}
+		*d = dir
+		return nil
+	}
+	return fmt.Errorf("invalid direction %q", text)
+}
@@ -0,0 +1,100 @@
+package geom
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestPoint_UnmarshalText(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    Point
+		wantErr bool
+	}{
+		{"3,4", Point{X: 3, Y: 4}, false},
+		{"-3,-4", Point{X: -3, Y: -4}, false},
+		{"3,4x", Point{}, true},
+		{"3,4,5", Point{}, true},
+		{"3, 4", Point{}, true},
+		{"3", Point{}, true},
+		{"", Point{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			var got Point
+			err := got.UnmarshalText([]byte(tt.text))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UnmarshalText(%q) error = %v, wantErr %v", tt.text, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("UnmarshalText(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPoint_jsonKeys(t *testing.T) {
+	want := map[Point]Direction{{X: 1, Y: -2}: DownLeft}
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"1,-2":"down-left"}` {
+		t.Errorf("json.Marshal() = %s", data)
+	}
+
+	var got map[Point]Direction
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[Point{X: 1, Y: -2}] != DownLeft {
+		t.Errorf("json.Unmarshal() = %v, want %v", got, want)
+	}
+}
+
+func TestParseDirection(t *testing.T) {
+	tests := []struct {
+		chars string
+		want  Direction
+	}{
+		{"^UN", Up},
+		{">RE", Right},
+		{"vDS", Down},
+		{"<LW", Left},
+		{"↗", UpRight},
+		{"↘", DownRight},
+		{"↙", DownLeft},
+		{"↖", UpLeft},
+	}
+	for _, tt := range tests {
+		for _, c := range tt.chars {
+			if got, err := ParseDirection(c); err != nil || got != tt.want {
+				t.Errorf("ParseDirection(%q) = %v, %v, want %v", c, got, err, tt.want)
+			}
+		}
+		// the arrow for a direction parses back to it
+		if got, err := ParseDirection(tt.want.Char()); err != nil || got != tt.want {
+			t.Errorf("ParseDirection(%q) = %v, %v, want %v", tt.want.Char(), got, err, tt.want)
+		}
+	}
+
+	for _, c := range "xu.#" {
+		if _, err := ParseDirection(c); err == nil {
+			t.Errorf("ParseDirection(%q) should fail", c)
+		}
+	}
+}
+
+func TestDirection_turns(t *testing.T) {
+	for _, d := range All {
+		if got := d.TurnRight().TurnLeft(); got != d {
+			t.Errorf("%v.TurnRight().TurnLeft() = %v", d, got)
+		}
+		if got := d.Offset().Add(d.TurnAround().Offset()); got != (Point{}) {
+			t.Errorf("%v and %v offsets don't cancel out", d, d.TurnAround())
+		}
+	}
+	if got := Up.Rotate45(1); got != UpRight {
+		t.Errorf("Up.Rotate45(1) = %v, want up-right", got)
+	}
+}
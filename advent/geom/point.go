@@ -0,0 +1,86 @@
+// Package geom has the points and directions used to move around the boards in each day
+package geom
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Point is a position on a board, or an offset between two positions. Y increases going down.
+type Point struct {
+	X int
+	Y int
+}
+
+// Add returns p moved by q
+func (p Point) Add(q Point) Point {
+	return Point{p.X + q.X, p.Y + q.Y}
+}
+
+// Sub returns the offset from q to p
+func (p Point) Sub(q Point) Point {
+	return Point{p.X - q.X, p.Y - q.Y}
+}
+
+// Scale multiplies both coordinates by k
+func (p Point) Scale(k int) Point {
+	return Point{p.X * k, p.Y * k}
+}
+
+// Neg returns the point with both coordinates negated
+func (p Point) Neg() Point {
+	return Point{-p.X, -p.Y}
+}
+
+// Move returns the next point in a direction
+func (p Point) Move(d Direction) Point {
+	return p.Add(d.Offset())
+}
+
+// Manhattan returns the distance between p and q moving only up, down, left and right
+func (p Point) Manhattan(q Point) int {
+	d := p.Sub(q)
+	return abs(d.X) + abs(d.Y)
+}
+
+// Chebyshev returns the distance between p and q when diagonal moves are allowed
+func (p Point) Chebyshev(q Point) int {
+	d := p.Sub(q)
+	return max(abs(d.X), abs(d.Y))
+}
+
+// String formats the point as x,y
+func (p Point) String() string {
+	return fmt.Sprintf("%d,%d", p.X, p.Y)
+}
+
+// MarshalText formats the point as x,y so points can be used as JSON keys
+func (p Point) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText parses a point formatted as x,y. Anything else, like spaces or trailing text, is an error.
+func (p *Point) UnmarshalText(text []byte) error {
+	xs, ys, ok := strings.Cut(string(text), ",")
+	if !ok {
+		return fmt.Errorf("invalid point %q: expected x,y", text)
+	}
+	x, err := strconv.Atoi(xs)
+	if err != nil {
+		return fmt.Errorf("invalid point %q: %w", text, err)
+	}
+	y, err := strconv.Atoi(ys)
+	if err != nil {
+		return fmt.Errorf("invalid point %q: %w", text, err)
+	}
+	*p = Point{X: x, Y: y}
+	return nil
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
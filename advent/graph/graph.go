@@ -0,0 +1,219 @@
+// Package graph holds directed graphs, like ordering rules, and sorts them topologically.
+package graph
+
+import (
+	"bufio"
+	"container/heap"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Edge is a directed edge between two nodes
+type Edge[T comparable] struct {
+	From T
+	To   T
+}
+
+// Graph is a directed graph. Nodes and edges are kept in the order they are added, so sorts and
+// output are stable.
+type Graph[T comparable] struct {
+	nodes []T
+	index map[T]int
+	out   map[T][]T
+	edges map[Edge[T]]bool
+	order []Edge[T]
+}
+
+// New creates an empty graph
+func New[T comparable]() *Graph[T] {
+	return &Graph[T]{index: map[T]int{}, out: map[T][]T{}, edges: map[Edge[T]]bool{}}
+}
+
+// AddNode adds a node if the graph doesn't have it yet
+func (g *Graph[T]) AddNode(n T) {
+	if _, ok := g.index[n]; ok {
+		return
+	}
+	g.index[n] = len(g.nodes)
+	g.nodes = append(g.nodes, n)
+}
+
+// AddEdge adds an edge, and its nodes if they're new
+func (g *Graph[T]) AddEdge(from, to T) {
+	g.AddNode(from)
+	g.AddNode(to)
+	e := Edge[T]{From: from, To: to}
+	if g.edges[e] {
+		return
+	}
+	g.edges[e] = true
+	g.order = append(g.order, e)
+	g.out[from] = append(g.out[from], to)
+}
+
+// HasEdge returns true if there is an edge from -> to
+func (g *Graph[T]) HasEdge(from, to T) bool {
+	return g.edges[Edge[T]{From: from, To: to}]
+}
+
+// Nodes are the nodes in the order they were added
+func (g *Graph[T]) Nodes() []T {
+	return g.nodes
+}
+
+// Edges are the edges in the order they were added
+func (g *Graph[T]) Edges() []Edge[T] {
+	return g.order
+}
+
+// Subgraph returns the graph of just these nodes and the edges between them. The nodes are
+// added in the order given, so a TopoSort of the subgraph keeps that order where it can.
+func (g *Graph[T]) Subgraph(nodes []T) *Graph[T] {
+	sub := New[T]()
+	for _, n := range nodes {
+		sub.AddNode(n)
+	}
+	for _, n := range sub.nodes {
+		for _, to := range g.out[n] {
+			if _, ok := sub.index[to]; ok {
+				sub.AddEdge(n, to)
+			}
+		}
+	}
+	return sub
+}
+
+// CycleError is returned when a graph can't be sorted because it has a cycle
+type CycleError[T comparable] struct {
+	// Cycle is the nodes of the cycle, starting and ending with the same node
+	Cycle []T
+}
+
+func (e *CycleError[T]) Error() string {
+	nodes := make([]string, len(e.Cycle))
+	for i, n := range e.Cycle {
+		nodes[i] = fmt.Sprint(n)
+	}
+	return "cycle: " + strings.Join(nodes, " -> ")
+}
+
+// TopoSort orders the nodes so every edge goes from an earlier node to a later one. When more than one
+// node could go next, the one added first goes first, so nodes already in order stay in order. If the
+// graph has a cycle it returns a *CycleError.
+func (g *Graph[T]) TopoSort() ([]T, error) {
+	inDegree := make([]int, len(g.nodes))
+	for _, e := range g.order {
+		inDegree[g.index[e.To]]++
+	}
+
+	// a node is ready when nothing left points at it. Ready nodes wait in a min heap of their
+	// index, so the earliest added goes next.
+	ready := &indexQueue{}
+	for i, d := range inDegree {
+		if d == 0 {
+			heap.Push(ready, i)
+		}
+	}
+
+	sorted := make([]T, 0, len(g.nodes))
+	for ready.Len() > 0 {
+		n := g.nodes[heap.Pop(ready).(int)]
+		sorted = append(sorted, n)
+		for _, to := range g.out[n] {
+			i := g.index[to]
+			inDegree[i]--
+			if inDegree[i] == 0 {
+				heap.Push(ready, i)
+			}
+		}
+	}
+
+	if len(sorted) < len(g.nodes) {
+		// the nodes left over all wait on each other
+		cycle, _ := g.FindCycle()
+		return nil, &CycleError[T]{Cycle: cycle}
+	}
+	return sorted, nil
+}
+
+// FindCycle returns a cycle in the graph, starting and ending with the same node, or false if there isn't one
+func (g *Graph[T]) FindCycle() ([]T, bool) {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[T]int, len(g.nodes))
+	var path []T
+
+	var visit func(n T) []T
+	visit = func(n T) []T {
+		state[n] = visiting
+		path = append(path, n)
+		for _, to := range g.out[n] {
+			switch state[to] {
+			case visiting:
+				// found the way back to a node on the path
+				for i, p := range path {
+					if p == to {
+						return append(append([]T{}, path[i:]...), to)
+					}
+				}
+			case unvisited:
+				if cycle := visit(to); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[n] = visited
+		return nil
+	}
+
+	for _, n := range g.nodes {
+		if state[n] == unvisited {
+			if cycle := visit(n); cycle != nil {
+				return cycle, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// WriteDOT writes the graph in Graphviz DOT format. edgeAttrs returns extra attributes for an edge,
+// like color=red, or "" for none.
+func (g *Graph[T]) WriteDOT(w io.Writer, name string, edgeAttrs func(e Edge[T]) string) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "digraph %q {\n", name)
+	for _, n := range g.nodes {
+		fmt.Fprintf(bw, "  %q;\n", fmt.Sprint(n))
+	}
+	for _, e := range g.order {
+		attrs := ""
+		if edgeAttrs != nil {
+			attrs = edgeAttrs(e)
+		}
+		if attrs != "" {
+			fmt.Fprintf(bw, "  %q -> %q [%s];\n", fmt.Sprint(e.From), fmt.Sprint(e.To), attrs)
+		} else {
+			fmt.Fprintf(bw, "  %q -> %q;\n", fmt.Sprint(e.From), fmt.Sprint(e.To))
+		}
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// indexQueue is a min heap of node indexes
+type indexQueue []int
+
+func (q indexQueue) Len() int           { return len(q) }
+func (q indexQueue) Less(i, j int) bool { return q[i] < q[j] }
+func (q indexQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *indexQueue) Push(x any)        { *q = append(*q, x.(int)) }
+func (q *indexQueue) Pop() any {
+	old := *q
+	n := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return n
+}
@@ -0,0 +1,86 @@
+package graph
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestTopoSort(t *testing.T) {
+	g := New[int]()
+	for _, e := range [][2]int{{97, 75}, {97, 47}, {75, 47}, {47, 61}, {61, 53}, {29, 13}, {47, 29}} {
+		g.AddEdge(e[0], e[1])
+	}
+
+	tests := []struct {
+		name   string
+		update []int
+		want   []int
+	}{
+		{"already sorted", []int{97, 75, 47, 61, 53}, []int{97, 75, 47, 61, 53}},
+		{"one swap", []int{75, 97, 47, 61, 53}, []int{97, 75, 47, 61, 53}},
+		{"reversed", []int{13, 29, 47}, []int{47, 29, 13}},
+		{"no rules keeps the order", []int{53, 13}, []int{53, 13}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := g.Subgraph(tt.update).TopoSort()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("TopoSort() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTopoSortCycle(t *testing.T) {
+	g := New[string]()
+	g.AddEdge("a", "b")
+	g.AddEdge("b", "c")
+	g.AddEdge("c", "a")
+	g.AddEdge("c", "d")
+
+	_, err := g.TopoSort()
+	var cycleErr *CycleError[string]
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("TopoSort() error = %v, want a CycleError", err)
+	}
+	if want := []string{"a", "b", "c", "a"}; !slices.Equal(cycleErr.Cycle, want) {
+		t.Errorf("TopoSort() cycle = %v, want %v", cycleErr.Cycle, want)
+	}
+
+	if _, ok := g.Subgraph([]string{"a", "b", "d"}).FindCycle(); ok {
+		t.Errorf("FindCycle() found a cycle without c")
+	}
+}
+
+func TestWriteDOT(t *testing.T) {
+	g := New[int]()
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+
+	var sb strings.Builder
+	err := g.WriteDOT(&sb, "rules", func(e Edge[int]) string {
+		if e.From == 2 {
+			return "color=red"
+		}
+		return ""
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `digraph "rules" {
+  "1";
+  "2";
+  "3";
+  "1" -> "2";
+  "2" -> "3" [color=red];
+}
+`
+	if sb.String() != want {
+		t.Errorf("WriteDOT() = %s, want %s", sb.String(), want)
+	}
+}
@@ -0,0 +1,330 @@
+package advent
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"strings"
+
+	"github.com/sirgwain/advent-of-code-2024/advent/geom"
+	"github.com/sirgwain/advent-of-code-2024/advent/parse"
+)
+
+// Grid is a fixed size 2D board of values, stored row by row. Positions outside the grid
+// are safe to read and return the zero value.
+type Grid[T comparable] struct {
+	width  int
+	height int
+	cells  []T
+}
+
+// allDirections are the cardinal directions followed by the diagonals
+var allDirections = geom.All
+
+// NewGrid creates a grid filled with the zero value
+func NewGrid[T comparable](width, height int) *Grid[T] {
+	return &Grid[T]{width: width, height: height, cells: make([]T, width*height)}
+}
+
+// GridFromSlices copies a 2D slice into a grid. Every row must be the same length.
+func GridFromSlices[T comparable](rows [][]T) (*Grid[T], error) {
+	if len(rows) == 0 {
+		return NewGrid[T](0, 0), nil
+	}
+	g := NewGrid[T](len(rows[0]), len(rows))
+	for y, row := range rows {
+		if len(row) != g.width {
+			return nil, fmt.Errorf("row %d has %d columns, expected %d", y, len(row), g.width)
+		}
+		copy(g.Row(y), row)
+	}
+	return g, nil
+}
+
+// ParseGrid reads a grid from r, one line per row, converting each character with parseCell.
+// Reading stops at the end of the input or the first blank line.
+func ParseGrid[T comparable](r io.Reader, parseCell func(c rune) (T, error)) (*Grid[T], error) {
+	return scanGrid(bufio.NewScanner(r), parseCell)
+}
+
+// scanGrid reads a grid from a scanner, stopping at a blank line so the scanner can
+// continue reading the rest of the input
+func scanGrid[T comparable](scanner *bufio.Scanner, parseCell func(c rune) (T, error)) (*Grid[T], error) {
+	g := &Grid[T]{}
+	for scanner.Scan() {
+		line := []rune(scanner.Text())
+		if len(line) == 0 {
+			break
+		}
+		if g.height == 0 {
+			g.width = len(line)
+		} else if len(line) != g.width {
+			return nil, &parse.Error{Line: g.height + 1, Text: string(line), Err: fmt.Errorf("row has %d columns, expected %d", len(line), g.width)}
+		}
+		for x, c := range line {
+			v, err := parseCell(c)
+			if err != nil {
+				return nil, &parse.Error{Line: g.height + 1, Column: x + 1, Text: string(line), Err: err}
+			}
+			g.cells = append(g.cells, v)
+		}
+		g.height++
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading grid: %w", err)
+	}
+	return g, nil
+}
+
+// parseRune keeps each character as is, for rune grids
+func parseRune(c rune) (rune, error) {
+	return c, nil
+}
+
+// parseDigit converts 0-9 into an int, for height maps and the like
+func parseDigit(c rune) (int, error) {
+	if c < '0' || c > '9' {
+		return 0, fmt.Errorf("%q is not a digit", c)
+	}
+	return int(c - '0'), nil
+}
+
+// readInputAsRuneGrid reads a file as a grid of characters
+func readInputAsRuneGrid(filename string) (*Grid[rune], error) {
+	return readInputAsGrid(filename, parseRune)
+}
+
+// readInputAsDigitGrid reads a file as a grid of single digit numbers
+func readInputAsDigitGrid(filename string) (*Grid[int], error) {
+	return readInputAsGrid(filename, parseDigit)
+}
+
+func readInputAsGrid[T comparable](filename string, parseCell func(c rune) (T, error)) (*Grid[T], error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("error opening file: %w", err)
+	}
+	defer file.Close()
+
+	g, err := ParseGrid(file, parseCell)
+	if err != nil {
+		var parseErr *parse.Error
+		if errors.As(err, &parseErr) {
+			parseErr.File = filename
+		}
+		return nil, err
+	}
+	return g, nil
+}
+
+// Size returns the width and height of the grid
+func (g *Grid[T]) Size() (width, height int) {
+	return g.width, g.height
+}
+
+func (g *Grid[T]) Width() int {
+	return g.width
+}
+
+func (g *Grid[T]) Height() int {
+	return g.height
+}
+
+// InBounds returns true if p is on the grid
+func (g *Grid[T]) InBounds(p position) bool {
+	return validPosition(p, g.width, g.height)
+}
+
+// Get returns the value at p, or the zero value if p is off the grid
+func (g *Grid[T]) Get(p position) T {
+	v, _ := g.Lookup(p)
+	return v
+}
+
+// Lookup returns the value at p and whether p is on the grid
+func (g *Grid[T]) Lookup(p position) (T, bool) {
+	if !g.InBounds(p) {
+		var zero T
+		return zero, false
+	}
+	return g.cells[p.Y*g.width+p.X], true
+}
+
+// Set sets the value at p, returning false if p is off the grid
+func (g *Grid[T]) Set(p position, v T) bool {
+	if !g.InBounds(p) {
+		return false
+	}
+	g.cells[p.Y*g.width+p.X] = v
+	return true
+}
+
+// Fill sets every position to v
+func (g *Grid[T]) Fill(v T) {
+	for i := range g.cells {
+		g.cells[i] = v
+	}
+}
+
+// Clone makes a deep copy of the grid
+func (g *Grid[T]) Clone() *Grid[T] {
+	clone := NewGrid[T](g.width, g.height)
+	copy(clone.cells, g.cells)
+	return clone
+}
+
+// Slices copies the grid into a 2D slice, for code that hasn't moved to Grid yet
+func (g *Grid[T]) Slices() [][]T {
+	s := make2DSlice[T](g.width, g.height)
+	for y := range s {
+		copy(s[y], g.Row(y))
+	}
+	return s
+}
+
+// Row returns row y. It shares storage with the grid, so setting values in the row updates the grid.
+func (g *Grid[T]) Row(y int) []T {
+	return g.cells[y*g.width : (y+1)*g.width : (y+1)*g.width]
+}
+
+// Column returns a copy of column x, or nil if x is off the grid
+func (g *Grid[T]) Column(x int) []T {
+	if x < 0 || x >= g.width {
+		return nil
+	}
+	column := make([]T, g.height)
+	for y := range column {
+		column[y] = g.cells[y*g.width+x]
+	}
+	return column
+}
+
+// Rows iterates over each row, top to bottom
+func (g *Grid[T]) Rows() iter.Seq2[int, []T] {
+	return func(yield func(int, []T) bool) {
+		for y := 0; y < g.height; y++ {
+			if !yield(y, g.Row(y)) {
+				return
+			}
+		}
+	}
+}
+
+// Columns iterates over a copy of each column, left to right
+func (g *Grid[T]) Columns() iter.Seq2[int, []T] {
+	return func(yield func(int, []T) bool) {
+		for x := 0; x < g.width; x++ {
+			if !yield(x, g.Column(x)) {
+				return
+			}
+		}
+	}
+}
+
+// All iterates over every position and value in row order
+func (g *Grid[T]) All() iter.Seq2[position, T] {
+	return func(yield func(position, T) bool) {
+		for i, v := range g.cells {
+			if !yield(position{X: i % g.width, Y: i / g.width}, v) {
+				return
+			}
+		}
+	}
+}
+
+// Neighbors iterates over the positions next to p in each direction that are on the grid.
+// Use cardinalDirections for up, down, left and right, or allDirections to include diagonals.
+func (g *Grid[T]) Neighbors(p position, dirs []direction) iter.Seq2[position, T] {
+	return func(yield func(position, T) bool) {
+		for _, dir := range dirs {
+			neighbor := p.Move(dir)
+			if v, ok := g.Lookup(neighbor); ok && !yield(neighbor, v) {
+				return
+			}
+		}
+	}
+}
+
+// Find returns the first position in row order with the value v
+func (g *Grid[T]) Find(v T) (position, bool) {
+	for p, value := range g.All() {
+		if value == v {
+			return p, true
+		}
+	}
+	return position{}, false
+}
+
+// FindAll returns every position in row order with the value v
+func (g *Grid[T]) FindAll(v T) []position {
+	var found []position
+	for p, value := range g.All() {
+		if value == v {
+			found = append(found, p)
+		}
+	}
+	return found
+}
+
+// Transpose returns a new grid with rows and columns swapped
+func (g *Grid[T]) Transpose() *Grid[T] {
+	t := NewGrid[T](g.height, g.width)
+	for p, v := range g.All() {
+		t.cells[p.X*t.width+p.Y] = v
+	}
+	return t
+}
+
+// RotateRight returns a new grid rotated 90 degrees clockwise
+func (g *Grid[T]) RotateRight() *Grid[T] {
+	return g.Transpose().FlipHorizontal()
+}
+
+// RotateLeft returns a new grid rotated 90 degrees counterclockwise
+func (g *Grid[T]) RotateLeft() *Grid[T] {
+	return g.Transpose().FlipVertical()
+}
+
+// FlipHorizontal returns a new grid mirrored left to right
+func (g *Grid[T]) FlipHorizontal() *Grid[T] {
+	f := NewGrid[T](g.width, g.height)
+	for p, v := range g.All() {
+		f.cells[p.Y*f.width+g.width-1-p.X] = v
+	}
+	return f
+}
+
+// FlipVertical returns a new grid mirrored top to bottom
+func (g *Grid[T]) FlipVertical() *Grid[T] {
+	f := NewGrid[T](g.width, g.height)
+	for y, row := range g.Rows() {
+		copy(f.Row(g.height-1-y), row)
+	}
+	return f
+}
+
+// Render draws the grid with one call to render per value, one line per row
+func (g *Grid[T]) Render(render func(p position, v T) string) string {
+	var sb strings.Builder
+	for p, v := range g.All() {
+		sb.WriteString(render(p, v))
+		if p.X == g.width-1 {
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+// String draws runes as is and everything else with fmt
+func (g *Grid[T]) String() string {
+	return g.Render(func(p position, v T) string {
+		if r, ok := any(v).(rune); ok {
+			return string(r)
+		}
+		return fmt.Sprint(v)
+	})
+}
@@ -0,0 +1,111 @@
+package advent
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sirgwain/advent-of-code-2024/advent/parse"
+)
+
+// a 3x2 grid, so rotations and transposes change the size
+const gridExample = `abc
+def`
+
+func TestGrid_transforms(t *testing.T) {
+	tests := []struct {
+		name      string
+		transform func(g *Grid[rune]) *Grid[rune]
+		want      string
+	}{
+		{"transpose", (*Grid[rune]).Transpose, "ad\nbe\ncf\n"},
+		{"rotate right", (*Grid[rune]).RotateRight, "da\neb\nfc\n"},
+		{"rotate left", (*Grid[rune]).RotateLeft, "cf\nbe\nad\n"},
+		{"flip horizontal", (*Grid[rune]).FlipHorizontal, "cba\nfed\n"},
+		{"flip vertical", (*Grid[rune]).FlipVertical, "def\nabc\n"},
+		{"rotate right and back", func(g *Grid[rune]) *Grid[rune] { return g.RotateRight().RotateLeft() }, "abc\ndef\n"},
+		{"rotate right four times", func(g *Grid[rune]) *Grid[rune] {
+			return g.RotateRight().RotateRight().RotateRight().RotateRight()
+		}, "abc\ndef\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := ParseGrid(strings.NewReader(gridExample), parseRune)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := tt.transform(g)
+			if got.String() != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+			// the original is never modified
+			if g.String() != gridExample+"\n" {
+				t.Errorf("original grid changed to\n%s", g)
+			}
+		})
+	}
+}
+
+func TestGrid_Row(t *testing.T) {
+	g, err := ParseGrid(strings.NewReader(gridExample), parseRune)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// rows share storage with the grid, but can't be appended into the next row
+	row := g.Row(0)
+	row[1] = 'B'
+	_ = append(row, 'x')
+	if got := g.String(); got != "aBc\ndef\n" {
+		t.Errorf("after updating row 0 got\n%s", got)
+	}
+
+	// columns are copies
+	g.Column(0)[0] = 'z'
+	if g.Get(position{X: 0, Y: 0}) != 'a' {
+		t.Errorf("updating a column changed the grid")
+	}
+
+	// columns off the grid don't read from the next row
+	for _, x := range []int{-1, 3, 4} {
+		if column := g.Column(x); column != nil {
+			t.Errorf("Column(%d) = %q, want nil", x, column)
+		}
+	}
+}
+
+func TestParseGrid_errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		line   int
+		column int
+	}{
+		{"ragged row", "123\n45\n678", 2, 0},
+		{"not a digit", "123\n4x6", 2, 2},
+		{"multibyte runes count as one column", "12é", 1, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseGrid(strings.NewReader(tt.input), parseDigit)
+			var parseErr *parse.Error
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("ParseGrid() error = %v, want a parse.Error", err)
+			}
+			if parseErr.Line != tt.line || parseErr.Column != tt.column {
+				t.Errorf("ParseGrid() error at %d:%d, want %d:%d", parseErr.Line, parseErr.Column, tt.line, tt.column)
+			}
+		})
+	}
+}
+
+func TestParseGrid_blankLine(t *testing.T) {
+	// reading stops at a blank line so the rest of the input can be read by someone else
+	g, err := ParseGrid(strings.NewReader("12\n34\n\nnot a grid"), parseDigit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w, h := g.Size(); w != 2 || h != 2 {
+		t.Errorf("ParseGrid() size = %dx%d, want 2x2", w, h)
+	}
+}
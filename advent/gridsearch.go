@@ -0,0 +1,15 @@
+package advent
+
+// gridNeighbors adapts a grid to a search.BFS neighbors function. It moves in each direction
+// to positions on the grid where canMove returns true.
+func gridNeighbors[T comparable](g *Grid[T], dirs []direction, canMove func(from, to position) bool) func(p position) []position {
+	return func(p position) []position {
+		var neighbors []position
+		for to := range g.Neighbors(p, dirs) {
+			if canMove == nil || canMove(p, to) {
+				neighbors = append(neighbors, to)
+			}
+		}
+		return neighbors
+	}
+}
@@ -0,0 +1,163 @@
+package mathx
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+)
+
+// ErrOverflow is returned when a result doesn't fit in an int
+var ErrOverflow = errors.New("integer overflow")
+
+// AddChecked returns a + b, or false if it overflows
+func AddChecked(a, b int) (int, bool) {
+	if (b > 0 && a > math.MaxInt-b) || (b < 0 && a < math.MinInt-b) {
+		return 0, false
+	}
+	return a + b, true
+}
+
+// SubChecked returns a - b, or false if it overflows
+func SubChecked(a, b int) (int, bool) {
+	if (b < 0 && a > math.MaxInt+b) || (b > 0 && a < math.MinInt+b) {
+		return 0, false
+	}
+	return a - b, true
+}
+
+// MulChecked returns a * b, or false if it overflows
+func MulChecked(a, b int) (int, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+	if (a == -1 && b == math.MinInt) || (b == -1 && a == math.MinInt) {
+		return 0, false
+	}
+	c := a * b
+	if c/b != a {
+		return 0, false
+	}
+	return c, true
+}
+
+// ConcatChecked appends the digits of b to a, or returns false if it overflows
+func ConcatChecked(a, b int) (int, bool) {
+	shift := 1
+	for range NumDigits(b) {
+		var ok bool
+		if shift, ok = MulChecked(shift, 10); !ok {
+			return 0, false
+		}
+	}
+	shifted, ok := MulChecked(a, shift)
+	if !ok {
+		return 0, false
+	}
+	return AddChecked(shifted, b)
+}
+
+// Solve2Checked is Solve2 with ErrOverflow if the intermediate products don't fit in an int
+func Solve2Checked(a, b, c, d, e, f int) (x, y int, ok bool, err error) {
+	products := [6]int{}
+	pairs := [6][2]int{{a, d}, {b, c}, {e, d}, {b, f}, {a, f}, {e, c}}
+	for i, pair := range pairs {
+		if products[i], ok = MulChecked(pair[0], pair[1]); !ok {
+			return 0, 0, false, ErrOverflow
+		}
+	}
+	det, ok1 := SubChecked(products[0], products[1])
+	xNum, ok2 := SubChecked(products[2], products[3])
+	yNum, ok3 := SubChecked(products[4], products[5])
+	if !ok1 || !ok2 || !ok3 {
+		return 0, 0, false, ErrOverflow
+	}
+	if det == 0 || xNum%det != 0 || yNum%det != 0 {
+		return 0, 0, false, nil
+	}
+	return xNum / det, yNum / det, true, nil
+}
+
+// Solve2Big is Solve2 with big integers
+func Solve2Big(a, b, c, d, e, f *big.Int) (x, y *big.Int, ok bool) {
+	mul := func(a, b *big.Int) *big.Int { return new(big.Int).Mul(a, b) }
+	det := new(big.Int).Sub(mul(a, d), mul(b, c))
+	if det.Sign() == 0 {
+		return nil, nil, false
+	}
+	xNum := new(big.Int).Sub(mul(e, d), mul(b, f))
+	yNum := new(big.Int).Sub(mul(a, f), mul(e, c))
+
+	x, xRem := new(big.Int).QuoRem(xNum, det, new(big.Int))
+	y, yRem := new(big.Int).QuoRem(yNum, det, new(big.Int))
+	if xRem.Sign() != 0 || yRem.Sign() != 0 {
+		return nil, nil, false
+	}
+	return x, y, true
+}
+
+// ConcatBig appends the digits of b to a
+func ConcatBig(a, b *big.Int) *big.Int {
+	digits := len(new(big.Int).Abs(b).String())
+	shift := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(digits)), nil)
+	return new(big.Int).Add(new(big.Int).Mul(a, shift), b)
+}
+
+// Sum adds up ints and switches to a big.Int if the total overflows. The zero value is an empty sum.
+type Sum struct {
+	small int
+	big   *big.Int
+}
+
+// UseBig switches the sum to big integers even if it hasn't overflowed
+func (s *Sum) UseBig() {
+	if s.big == nil {
+		s.big = big.NewInt(int64(s.small))
+	}
+}
+
+// Add adds n to the sum
+func (s *Sum) Add(n int) {
+	if s.big == nil {
+		if total, ok := AddChecked(s.small, n); ok {
+			s.small = total
+			return
+		}
+	}
+	s.AddBig(big.NewInt(int64(n)))
+}
+
+// AddBig adds a big n to the sum, staying an int if the total still fits
+func (s *Sum) AddBig(n *big.Int) {
+	if s.big == nil && n.IsInt64() {
+		if total, ok := AddChecked(s.small, int(n.Int64())); ok {
+			s.small = total
+			return
+		}
+	}
+	s.UseBig()
+	s.big.Add(s.big, n)
+}
+
+// IsBig returns true if the sum has overflowed an int or was switched to big integers
+func (s *Sum) IsBig() bool {
+	return s.big != nil
+}
+
+// Int returns the sum and false if it doesn't fit in an int
+func (s *Sum) Int() (int, bool) {
+	if s.big == nil {
+		return s.small, true
+	}
+	if !s.big.IsInt64() {
+		return 0, false
+	}
+	return int(s.big.Int64()), true
+}
+
+func (s *Sum) String() string {
+	if s.big == nil {
+		return strconv.Itoa(s.small)
+	}
+	return s.big.String()
+}
@@ -0,0 +1,43 @@
+package mathx
+
+// Pow10 returns 10^n for n >= 0
+func Pow10(n int) int {
+	p := 1
+	for range n {
+		p *= 10
+	}
+	return p
+}
+
+// NumDigits returns how many decimal digits n has, ignoring the sign. 0 has one digit.
+func NumDigits(n int) int {
+	n = Abs(n)
+	digits := 1
+	for n >= 10 {
+		n /= 10
+		digits++
+	}
+	return digits
+}
+
+// SplitDigits splits n into the digits before and after the last count digits, so 1234, 2 is 12, 34
+func SplitDigits(n, count int) (high, low int) {
+	k := Pow10(count)
+	return n / k, n % k
+}
+
+// Concat appends the digits of b to a, so 12, 345 is 12345
+func Concat(a, b int) int {
+	return a*Pow10(NumDigits(b)) + b
+}
+
+// Digits returns the decimal digits of n, most significant first, ignoring the sign
+func Digits(n int) []int {
+	digits := make([]int, NumDigits(n))
+	n = Abs(n)
+	for i := len(digits) - 1; i >= 0; i-- {
+		digits[i] = n % 10
+		n /= 10
+	}
+	return digits
+}
@@ -0,0 +1,99 @@
+package mathx
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// ErrSingular is returned when a system of equations doesn't have a single solution
+var ErrSingular = errors.New("singular matrix")
+
+// Solve2 solves the 2x2 system
+//
+//	a*x + b*y = e
+//	c*x + d*y = f
+//
+// with Cramer's rule. ok is false if there isn't a single solution in whole numbers.
+func Solve2(a, b, c, d, e, f int) (x, y int, ok bool) {
+	det := a*d - b*c
+	if det == 0 {
+		return 0, 0, false
+	}
+	xNum := e*d - b*f
+	yNum := a*f - e*c
+	if xNum%det != 0 || yNum%det != 0 {
+		return 0, 0, false
+	}
+	return xNum / det, yNum / det, true
+}
+
+// SolveRat solves a*x = b exactly with gaussian elimination over the rationals.
+// a is a square matrix of rows.
+func SolveRat(a [][]int, b []int) ([]*big.Rat, error) {
+	n := len(a)
+	if len(b) != n {
+		return nil, fmt.Errorf("%d rows but %d values", n, len(b))
+	}
+
+	// augmented matrix [a | b]
+	m := make([][]*big.Rat, n)
+	for i, row := range a {
+		if len(row) != n {
+			return nil, fmt.Errorf("row %d has %d columns, expected %d", i, len(row), n)
+		}
+		m[i] = make([]*big.Rat, n+1)
+		for j, v := range row {
+			m[i][j] = big.NewRat(int64(v), 1)
+		}
+		m[i][n] = big.NewRat(int64(b[i]), 1)
+	}
+
+	for col := 0; col < n; col++ {
+		// find a row with a non zero pivot
+		pivot := -1
+		for row := col; row < n; row++ {
+			if m[row][col].Sign() != 0 {
+				pivot = row
+				break
+			}
+		}
+		if pivot == -1 {
+			return nil, ErrSingular
+		}
+		m[col], m[pivot] = m[pivot], m[col]
+
+		// clear this column in every other row
+		for row := 0; row < n; row++ {
+			if row == col || m[row][col].Sign() == 0 {
+				continue
+			}
+			factor := new(big.Rat).Quo(m[row][col], m[col][col])
+			for j := col; j <= n; j++ {
+				m[row][j].Sub(m[row][j], new(big.Rat).Mul(factor, m[col][j]))
+			}
+		}
+	}
+
+	x := make([]*big.Rat, n)
+	for i := range x {
+		x[i] = new(big.Rat).Quo(m[i][n], m[i][i])
+	}
+	return x, nil
+}
+
+// SolveInt solves a*x = b exactly. ok is false if the solution isn't all whole numbers that fit in an int.
+func SolveInt(a [][]int, b []int) (x []int, ok bool, err error) {
+	solution, err := SolveRat(a, b)
+	if err != nil {
+		return nil, false, err
+	}
+	x = make([]int, len(solution))
+	for i, v := range solution {
+		if !v.IsInt() || !v.Num().IsInt64() {
+			return nil, false, nil
+		}
+		x[i] = int(v.Num().Int64())
+	}
+	return x, true, nil
+}
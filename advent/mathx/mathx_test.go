@@ -0,0 +1,104 @@
+package mathx
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestCRT(t *testing.T) {
+	tests := []struct {
+		name     string
+		residues []int
+		moduli   []int
+		want     int
+		wantM    int
+		wantErr  bool
+	}{
+		{"coprime", []int{2, 3, 2}, []int{3, 5, 7}, 23, 105, false},
+		{"robots", []int{99, 67}, []int{101, 103}, 1715, 10403, false},
+		{"not coprime", []int{2, 4}, []int{6, 8}, 20, 24, false},
+		{"no solution", []int{1, 2}, []int{4, 6}, 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, m, err := CRT(tt.residues, tt.moduli)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CRT() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want || m != tt.wantM {
+				t.Errorf("CRT() = %d, %d, want %d, %d", got, m, tt.want, tt.wantM)
+			}
+		})
+	}
+}
+
+func TestModInv(t *testing.T) {
+	if got, err := ModInv(3, 11); err != nil || got != 4 {
+		t.Errorf("ModInv(3, 11) = %d, %v, want 4", got, err)
+	}
+	if _, err := ModInv(4, 8); err == nil {
+		t.Errorf("ModInv(4, 8) should have no inverse")
+	}
+}
+
+func TestDigits(t *testing.T) {
+	if got := Concat(12, 345); got != 12345 {
+		t.Errorf("Concat(12, 345) = %d, want 12345", got)
+	}
+	if high, low := SplitDigits(1000, 2); high != 10 || low != 0 {
+		t.Errorf("SplitDigits(1000, 2) = %d, %d, want 10, 0", high, low)
+	}
+	if got := Digits(907); !slices.Equal(got, []int{9, 0, 7}) {
+		t.Errorf("Digits(907) = %v", got)
+	}
+}
+
+func TestSolve(t *testing.T) {
+	// the first claw machine
+	if a, b, ok := Solve2(94, 22, 34, 67, 8400, 5400); !ok || a != 80 || b != 40 {
+		t.Errorf("Solve2() = %d, %d, %v, want 80, 40", a, b, ok)
+	}
+
+	x, ok, err := SolveInt([][]int{{2, 1, -1}, {-3, -1, 2}, {-2, 1, 2}}, []int{8, -11, -3})
+	if err != nil || !ok || !slices.Equal(x, []int{2, 3, -1}) {
+		t.Errorf("SolveInt() = %v, %v, %v, want [2 3 -1]", x, ok, err)
+	}
+
+	if _, _, err := SolveInt([][]int{{1, 2}, {2, 4}}, []int{1, 2}); err == nil {
+		t.Errorf("SolveInt() of a singular matrix should fail")
+	}
+}
+
+func TestChecked(t *testing.T) {
+	if _, ok := AddChecked(math.MaxInt, 1); ok {
+		t.Errorf("AddChecked(MaxInt, 1) should overflow")
+	}
+	if got, ok := MulChecked(1<<31, 1<<31); !ok || got != 1<<62 {
+		t.Errorf("MulChecked(1<<31, 1<<31) = %d, %v, want %d", got, ok, 1<<62)
+	}
+	if _, ok := MulChecked(1<<32, 1<<31); ok {
+		t.Errorf("MulChecked(1<<32, 1<<31) should overflow")
+	}
+	if got, ok := ConcatChecked(12, 345); !ok || got != 12345 {
+		t.Errorf("ConcatChecked(12, 345) = %d, %v, want 12345", got, ok)
+	}
+	if _, ok := ConcatChecked(math.MaxInt/10, 10); ok {
+		t.Errorf("ConcatChecked(MaxInt/10, 10) should overflow")
+	}
+}
+
+func TestSum(t *testing.T) {
+	var sum Sum
+	sum.Add(math.MaxInt)
+	if sum.IsBig() {
+		t.Fatalf("Sum of MaxInt should fit in an int")
+	}
+	sum.Add(math.MaxInt)
+	if !sum.IsBig() {
+		t.Fatalf("Sum of 2 * MaxInt should be big")
+	}
+	if got, want := sum.String(), "18446744073709551614"; got != want {
+		t.Errorf("Sum = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,104 @@
+// Package mathx has the number theory, digit and linear algebra helpers that keep coming up in puzzles
+package mathx
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNoSolution is returned when a system of equations or congruences has no solution
+var ErrNoSolution = errors.New("no solution")
+
+// Abs returns the absolute value of n
+func Abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// GCD returns the greatest common divisor of a and b, always positive or 0
+func GCD(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return Abs(a)
+}
+
+// LCM returns the least common multiple of all the numbers
+func LCM(nums ...int) int {
+	if len(nums) == 0 {
+		return 0
+	}
+	lcm := Abs(nums[0])
+	for _, n := range nums[1:] {
+		if lcm == 0 || n == 0 {
+			return 0
+		}
+		lcm = lcm / GCD(lcm, n) * Abs(n)
+	}
+	return lcm
+}
+
+// ExtGCD returns the gcd of a and b and x, y with a*x + b*y = gcd
+func ExtGCD(a, b int) (gcd, x, y int) {
+	oldR, r := a, b
+	oldX, x := 1, 0
+	oldY, y := 0, 1
+	for r != 0 {
+		q := oldR / r
+		oldR, r = r, oldR-q*r
+		oldX, x = x, oldX-q*x
+		oldY, y = y, oldY-q*y
+	}
+	if oldR < 0 {
+		return -oldR, -oldX, -oldY
+	}
+	return oldR, oldX, oldY
+}
+
+// Mod returns a mod m in the range [0, m), unlike % which keeps the sign of a
+func Mod(a, m int) int {
+	a %= m
+	if a < 0 {
+		a += Abs(m)
+	}
+	return a
+}
+
+// ModInv returns x with a*x = 1 mod m
+func ModInv(a, m int) (int, error) {
+	gcd, x, _ := ExtGCD(Mod(a, m), m)
+	if gcd != 1 {
+		return 0, fmt.Errorf("%d has no inverse mod %d: %w", a, m, ErrNoSolution)
+	}
+	return Mod(x, m), nil
+}
+
+// CRT solves x = residues[i] mod moduli[i] for every i with the chinese remainder theorem.
+// It returns the smallest non-negative x and the lcm of the moduli, which all solutions are a multiple of apart.
+// The moduli don't have to be coprime, but then there may not be a solution.
+func CRT(residues, moduli []int) (x, m int, err error) {
+	if len(residues) != len(moduli) {
+		return 0, 0, fmt.Errorf("%d residues for %d moduli", len(residues), len(moduli))
+	}
+
+	x, m = 0, 1
+	for i, mi := range moduli {
+		if mi <= 0 {
+			return 0, 0, fmt.Errorf("modulus %d must be positive", mi)
+		}
+		ri := Mod(residues[i], mi)
+
+		// solve x + m*k = ri mod mi for k
+		gcd, inv, _ := ExtGCD(m, mi)
+		if (ri-x)%gcd != 0 {
+			return 0, 0, fmt.Errorf("x = %d mod %d and x = %d mod %d: %w", x, m, ri, mi, ErrNoSolution)
+		}
+		k := Mod((ri-x)/gcd*inv, mi/gcd)
+		x += m * k
+		m = m / gcd * mi
+		x = Mod(x, m)
+	}
+	return x, m, nil
+}
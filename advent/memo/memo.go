@@ -0,0 +1,156 @@
+// Package memo caches the results of recursive functions
+package memo
+
+import (
+	"container/list"
+	"fmt"
+	"log/slog"
+	"sync"
+)
+
+// Func is a function to memoize. It calls recurse instead of itself so the recursive calls are cached too.
+type Func[K comparable, V any] func(recurse func(K) V, key K) V
+
+// Memo caches the results of a function by its key. It's safe to use from multiple goroutines,
+// but two goroutines asking for the same missing key at once may both compute it.
+type Memo[K comparable, V any] struct {
+	fn    Func[K, V]
+	limit int
+
+	mu      sync.Mutex
+	entries map[K]*entry[K, V]
+	// least recently used entries are at the back, only kept when there is a limit
+	recent *list.List
+	stats  Stats
+}
+
+type entry[K comparable, V any] struct {
+	value V
+	elem  *list.Element
+}
+
+// Stats are counts of how well the cache is doing
+type Stats struct {
+	Hits      int
+	Misses    int
+	Evictions int
+	Size      int
+}
+
+// HitRate is the fraction of lookups that were cached
+func (s Stats) HitRate() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+func (s Stats) String() string {
+	return fmt.Sprintf("hits: %d, misses: %d, evictions: %d, size: %d, hit rate: %.1f%%", s.Hits, s.Misses, s.Evictions, s.Size, s.HitRate()*100)
+}
+
+// LogValue logs stats as a group
+func (s Stats) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Int("hits", s.Hits),
+		slog.Int("misses", s.Misses),
+		slog.Int("evictions", s.Evictions),
+		slog.Int("size", s.Size),
+	)
+}
+
+type Option[K comparable, V any] func(m *Memo[K, V])
+
+// WithLimit keeps at most limit results, dropping the least recently used. 0 means no limit.
+func WithLimit[K comparable, V any](limit int) Option[K, V] {
+	return func(m *Memo[K, V]) {
+		m.limit = limit
+	}
+}
+
+// New memoizes fn
+func New[K comparable, V any](fn Func[K, V], opts ...Option[K, V]) *Memo[K, V] {
+	m := &Memo[K, V]{fn: fn}
+	for _, opt := range opts {
+		opt(m)
+	}
+	m.Reset()
+	return m
+}
+
+// Get returns the cached result for key, or calls the function and caches it
+func (m *Memo[K, V]) Get(key K) V {
+	if v, ok := m.lookup(key); ok {
+		return v
+	}
+
+	// don't hold the lock while computing, the function calls back into Get
+	v := m.fn(m.Get, key)
+	m.store(key, v)
+	return v
+}
+
+func (m *Memo[K, V]) lookup(key K) (V, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.entries[key]
+	if !ok {
+		m.stats.Misses++
+		var zero V
+		return zero, false
+	}
+	m.stats.Hits++
+	if e.elem != nil {
+		m.recent.MoveToFront(e.elem)
+	}
+	return e.value, true
+}
+
+func (m *Memo[K, V]) store(key K, v V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if e, ok := m.entries[key]; ok {
+		// another goroutine beat us to it
+		e.value = v
+		return
+	}
+
+	e := &entry[K, V]{value: v}
+	if m.limit > 0 {
+		e.elem = m.recent.PushFront(key)
+		for len(m.entries) >= m.limit {
+			oldest := m.recent.Back()
+			m.recent.Remove(oldest)
+			delete(m.entries, oldest.Value.(K))
+			m.stats.Evictions++
+		}
+	}
+	m.entries[key] = e
+}
+
+// Len returns the number of cached results
+func (m *Memo[K, V]) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.entries)
+}
+
+// Stats returns the hits, misses, evictions and size so far
+func (m *Memo[K, V]) Stats() Stats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stats := m.stats
+	stats.Size = len(m.entries)
+	return stats
+}
+
+// Reset clears the cache and its stats
+func (m *Memo[K, V]) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = make(map[K]*entry[K, V])
+	m.recent = list.New()
+	m.stats = Stats{}
+}
@@ -0,0 +1,47 @@
+package memo
+
+import (
+	"sync"
+	"testing"
+)
+
+func fib(recurse func(int) int, n int) int {
+	if n < 2 {
+		return n
+	}
+	return recurse(n-1) + recurse(n-2)
+}
+
+func TestMemo(t *testing.T) {
+	m := New(fib)
+	if got := m.Get(50); got != 12586269025 {
+		t.Errorf("Get(50) = %d, want 12586269025", got)
+	}
+	stats := m.Stats()
+	if stats.Size != 51 || stats.Misses != 51 || stats.Hits != 48 {
+		t.Errorf("Stats() = %v, want 51 misses, 48 hits, size 51", stats)
+	}
+
+	// concurrent gets all agree
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got := m.Get(60); got != 1548008755920 {
+				t.Errorf("Get(60) = %d, want 1548008755920", got)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestMemo_limit(t *testing.T) {
+	m := New(fib, WithLimit[int, int](10))
+	if got := m.Get(40); got != 102334155 {
+		t.Errorf("Get(40) = %d, want 102334155", got)
+	}
+	if stats := m.Stats(); stats.Size != 10 || stats.Evictions != 31 {
+		t.Errorf("Stats() = %v, want size 10, 31 evictions", stats)
+	}
+}
@@ -1,10 +1,25 @@
 package advent
 
+import (
+	"runtime"
+
+	"github.com/sirgwain/advent-of-code-2024/advent/tui"
+)
+
 // Options holds the configurable parameters for a service or feature.
 type Options struct {
 	Delay            int
 	UpdateOnNumMoves int
 	RedactSolution   bool
+	RedactPolicy     RedactPolicy
+	RedactRatio      float64
+	RedactDistance   int
+	RenderMode       tui.RenderMode
+	Workers          int
+	BigInt           bool
+	Explain          bool
+	Words            []string
+	DotFile          string
 }
 
 // Option is a functional option type that modifies the Options.
@@ -31,12 +46,80 @@ func WithRedactSolution(redact bool) Option {
 	}
 }
 
+// WithRedactPolicy sets the RedactPolicy option.
+func WithRedactPolicy(policy RedactPolicy) Option {
+	return func(o *Options) {
+		o.RedactPolicy = policy
+	}
+}
+
+// WithRedactRatio sets the RedactRatio option.
+func WithRedactRatio(ratio float64) Option {
+	return func(o *Options) {
+		o.RedactRatio = ratio
+	}
+}
+
+// WithRedactDistance sets the RedactDistance option.
+func WithRedactDistance(distance int) Option {
+	return func(o *Options) {
+		o.RedactDistance = distance
+	}
+}
+
+// WithRenderMode sets the RenderMode option.
+func WithRenderMode(mode tui.RenderMode) Option {
+	return func(o *Options) {
+		o.RenderMode = mode
+	}
+}
+
+// WithWorkers sets the Workers option.
+func WithWorkers(workers int) Option {
+	return func(o *Options) {
+		o.Workers = workers
+	}
+}
+
+// WithBigInt sets the BigInt option.
+func WithBigInt(bigInt bool) Option {
+	return func(o *Options) {
+		o.BigInt = bigInt
+	}
+}
+
+// WithExplain sets the Explain option.
+func WithExplain(explain bool) Option {
+	return func(o *Options) {
+		o.Explain = explain
+	}
+}
+
+// WithWords sets the Words option.
+func WithWords(words []string) Option {
+	return func(o *Options) {
+		o.Words = words
+	}
+}
+
+// WithDotFile sets the DotFile option.
+func WithDotFile(filename string) Option {
+	return func(o *Options) {
+		o.DotFile = filename
+	}
+}
+
 func newRun(opts ...Option) *Options {
 	// Default options
 	options := &Options{
 		Delay:            0,
 		UpdateOnNumMoves: 0,
 		RedactSolution:   false,
+		RedactPolicy:     RedactRatio,
+		RedactRatio:      .75,
+		RedactDistance:   100,
+		RenderMode:       tui.RenderAuto,
+		Workers:          runtime.GOMAXPROCS(0),
 	}
 
 	// Apply provided options
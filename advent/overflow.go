@@ -0,0 +1,8 @@
+package advent
+
+import "fmt"
+
+// overflowWarning explains that a value was too big for an int, so it was worked out with big integers instead
+func overflowWarning(what string) string {
+	return fmt.Sprintf("%s %s is too big for an int, using big integers", incorrectResultStyle.Render("overflow:"), what)
+}
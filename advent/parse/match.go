@@ -0,0 +1,128 @@
+package parse
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Matcher matches lines against a regex and sets struct fields from its named groups.
+// Fields are tagged with the group name, like `parse:"x"` for (?P<x>\d+).
+// Supported fields are strings and the int, uint and float kinds. A rune field is an int32
+// like any other unless it's tagged with the rune option, like `parse:"op,rune"`, to take a
+// single character instead of a number.
+type Matcher[T any] struct {
+	re *regexp.Regexp
+	// the field for each group, with an index of -1 if the group isn't used
+	fields []matchField
+}
+
+// matchField is a struct field set from a group
+type matchField struct {
+	index int
+	rune  bool
+}
+
+// NewMatcher compiles a pattern for T. Every tagged field must be exported and have a matching named group.
+func NewMatcher[T any](pattern string) (*Matcher[T], error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("bad regex %s %w", pattern, err)
+	}
+
+	t := reflect.TypeFor[T]()
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("matcher type %v is not a struct", t)
+	}
+
+	m := &Matcher[T]{re: re, fields: make([]matchField, re.NumSubexp()+1)}
+	for i := range m.fields {
+		m.fields[i].index = -1
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, ok := field.Tag.Lookup("parse")
+		if !ok {
+			continue
+		}
+		name, option, _ := strings.Cut(tag, ",")
+		switch option {
+		case "":
+		case "rune":
+			if field.Type.Kind() != reflect.Int32 {
+				return nil, fmt.Errorf("field %s has the rune option but is a %v, not a rune", field.Name, field.Type)
+			}
+		default:
+			return nil, fmt.Errorf("field %s has unknown option %s", field.Name, option)
+		}
+		if !field.IsExported() {
+			return nil, fmt.Errorf("field %s is tagged but unexported, so it can't be set", field.Name)
+		}
+		group := re.SubexpIndex(name)
+		if group == -1 {
+			return nil, fmt.Errorf("field %s has no group named %s in %s", field.Name, name, pattern)
+		}
+		m.fields[group] = matchField{index: i, rune: option == "rune"}
+	}
+	return m, nil
+}
+
+// Match parses a line. It's an error if the line doesn't match or a group can't be converted to its field.
+func (m *Matcher[T]) Match(l Line) (T, error) {
+	var value T
+	loc := m.re.FindStringSubmatchIndex(l.Text)
+	if loc == nil {
+		return value, l.Errorf(0, "line doesn't match %s", m.re)
+	}
+
+	v := reflect.ValueOf(&value).Elem()
+	for group, field := range m.fields {
+		if field.index == -1 || loc[group*2] == -1 {
+			continue
+		}
+		start, end := loc[group*2], loc[group*2+1]
+		if err := setField(v.Field(field.index), l.Text[start:end], field.rune); err != nil {
+			return value, l.Errorf(l.column(start), "%s: %w", v.Type().Field(field.index).Name, err)
+		}
+	}
+	return value, nil
+}
+
+func setField(field reflect.Value, text string, isRune bool) error {
+	if isRune {
+		runes := []rune(text)
+		if len(runes) != 1 {
+			return fmt.Errorf("expected a single character, found %q", text)
+		}
+		field.SetInt(int64(runes[0]))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(text)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(text, 10, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid number: %w", err)
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(text, 10, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid number: %w", err)
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(text, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid number: %w", err)
+		}
+		field.SetFloat(n)
+	default:
+		return fmt.Errorf("unsupported field type %v", field.Type())
+	}
+	return nil
+}
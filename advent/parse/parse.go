@@ -0,0 +1,182 @@
+// Package parse reads puzzle inputs into sections and lines, pulls numbers and regex matches
+// out of lines, and reports errors with the file, line and column they came from.
+package parse
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Error is a problem with the input and where it was found
+type Error struct {
+	File string
+	// Line and Column start at 1. Column counts runes, not bytes, and is 0 if the whole line is the problem.
+	Line   int
+	Column int
+	// Text is the line with the problem
+	Text string
+	Err  error
+}
+
+func (e *Error) Error() string {
+	var sb strings.Builder
+	if e.File != "" {
+		sb.WriteString(e.File + ":")
+	}
+	fmt.Fprintf(&sb, "%d:", e.Line)
+	if e.Column > 0 {
+		fmt.Fprintf(&sb, "%d:", e.Column)
+	}
+	fmt.Fprintf(&sb, " %v: %q", e.Err, e.Text)
+	return sb.String()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Pointer shows the line with a caret under the column, or just the line if there is no column
+func (e *Error) Pointer() string {
+	if e.Column == 0 {
+		return e.Text
+	}
+	// keep tabs so the caret lines up
+	indent := strings.Map(func(r rune) rune {
+		if r == '\t' {
+			return r
+		}
+		return ' '
+	}, string([]rune(e.Text)[:min(e.Column-1, len([]rune(e.Text)))]))
+	return e.Text + "\n" + indent + "^"
+}
+
+// Line is one line of input
+type Line struct {
+	File string
+	// Num is the line number, starting at 1
+	Num  int
+	Text string
+}
+
+// Errorf creates an Error for this line at a column, starting at 1, or 0 for the whole line
+func (l Line) Errorf(column int, format string, args ...any) error {
+	return &Error{File: l.File, Line: l.Num, Column: column, Text: l.Text, Err: fmt.Errorf(format, args...)}
+}
+
+// column converts a byte offset in the line, like a regex match index, to a column
+func (l Line) column(offset int) int {
+	return utf8.RuneCountInString(l.Text[:offset]) + 1
+}
+
+// Input is a whole input file
+type Input struct {
+	File  string
+	Lines []Line
+}
+
+// Section is a group of lines separated from other sections by blank lines
+type Section []Line
+
+// ReadFile reads an input file
+func ReadFile(filename string) (*Input, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("error opening file: %w", err)
+	}
+	defer file.Close()
+
+	return Read(file, filename)
+}
+
+// Read reads input from r. The name is used in errors, like a filename.
+func Read(r io.Reader, name string) (*Input, error) {
+	input := &Input{File: name}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		input.Lines = append(input.Lines, Line{File: name, Num: len(input.Lines) + 1, Text: scanner.Text()})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", name, err)
+	}
+	return input, nil
+}
+
+// Sections splits the input on blank lines. Extra blank lines are skipped.
+func (in *Input) Sections() []Section {
+	var sections []Section
+	var section Section
+	for _, line := range in.Lines {
+		if strings.TrimSpace(line.Text) == "" {
+			if len(section) > 0 {
+				sections = append(sections, section)
+				section = nil
+			}
+			continue
+		}
+		section = append(section, line)
+	}
+	if len(section) > 0 {
+		sections = append(sections, section)
+	}
+	return sections
+}
+
+// SectionsN splits the input on blank lines and expects exactly n sections
+func (in *Input) SectionsN(n int) ([]Section, error) {
+	sections := in.Sections()
+	if len(sections) != n {
+		return nil, &Error{File: in.File, Line: len(in.Lines), Err: fmt.Errorf("expected %d sections separated by blank lines, found %d", n, len(sections))}
+	}
+	return sections, nil
+}
+
+// Expect returns an error if the line doesn't match re, for checking a line's format before pulling values out of it
+func (l Line) Expect(re *regexp.Regexp) error {
+	if !re.MatchString(l.Text) {
+		return l.Errorf(0, "line doesn't match %s", re)
+	}
+	return nil
+}
+
+var intPattern = regexp.MustCompile(`[-+]?\d+`)
+
+// Ints returns all the signed integers in the line. A - or + is only a sign if it's not
+// right after a digit, so ranges like 1-3 are two positive numbers.
+func (l Line) Ints() ([]int, error) {
+	var ints []int
+	for _, loc := range intPattern.FindAllStringIndex(l.Text, -1) {
+		start := loc[0]
+		if start > 0 && isDigit(l.Text[start-1]) {
+			// a separator, not a sign
+			start++
+		}
+		n, err := strconv.Atoi(l.Text[start:loc[1]])
+		if err != nil {
+			return nil, l.Errorf(l.column(start), "invalid number: %w", err)
+		}
+		ints = append(ints, n)
+	}
+	return ints, nil
+}
+
+// IntsN returns the signed integers in the line and expects exactly n of them
+func (l Line) IntsN(n int) ([]int, error) {
+	ints, err := l.Ints()
+	if err != nil {
+		return nil, err
+	}
+	if len(ints) != n {
+		return nil, l.Errorf(0, "expected %d numbers, found %d", n, len(ints))
+	}
+	return ints, nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,254 @@
+package parse
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func mustRead(t *testing.T, text string) *Input {
+	t.Helper()
+	input, err := Read(strings.NewReader(text), "test.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return input
+}
+
+// lineNums are the line numbers of each section, to check where sections start and end
+func lineNums(sections []Section) [][]int {
+	var nums [][]int
+	for _, section := range sections {
+		var n []int
+		for _, line := range section {
+			n = append(n, line.Num)
+		}
+		nums = append(nums, n)
+	}
+	return nums
+}
+
+func TestInput_Sections(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  [][]int
+	}{
+		{"one section", "a\nb", [][]int{{1, 2}}},
+		{"two sections", "a\nb\n\nc", [][]int{{1, 2}, {4}}},
+		{"extra blank lines", "\n\na\n\n\n\nb\n\n", [][]int{{3}, {7}}},
+		{"whitespace is blank", "a\n  \t\nb", [][]int{{1}, {3}}},
+		{"empty", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := lineNums(mustRead(t, tt.input).Sections())
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Sections() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInput_SectionsN(t *testing.T) {
+	input := mustRead(t, "a\n\nb\n\nc")
+	if sections, err := input.SectionsN(3); err != nil || len(sections) != 3 {
+		t.Errorf("SectionsN(3) = %d sections, %v", len(sections), err)
+	}
+
+	_, err := input.SectionsN(2)
+	var parseErr *Error
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("SectionsN(2) error = %v, want an Error", err)
+	}
+	if parseErr.File != "test.txt" || parseErr.Line != 5 {
+		t.Errorf("SectionsN(2) error at %s:%d, want test.txt:5", parseErr.File, parseErr.Line)
+	}
+}
+
+func TestLine_Ints(t *testing.T) {
+	tests := []struct {
+		text string
+		want []int
+	}{
+		{"1 2 3", []int{1, 2, 3}},
+		{"-5", []int{-5}},
+		{"+5", []int{5}},
+		{"1-3", []int{1, 3}},
+		{"1--3", []int{1, -3}},
+		{"p=0,4 v=-3,+2", []int{0, 4, -3, 2}},
+		{"x-1", []int{-1}},
+		{"no numbers", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, err := Line{Num: 1, Text: tt.text}.Ints()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Ints() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLine_IntsN(t *testing.T) {
+	line := Line{Num: 1, Text: "1|2"}
+	if got, err := line.IntsN(2); err != nil || !reflect.DeepEqual(got, []int{1, 2}) {
+		t.Errorf("IntsN(2) = %v, %v", got, err)
+	}
+	if _, err := line.IntsN(3); err == nil {
+		t.Errorf("IntsN(3) should fail")
+	}
+}
+
+func TestLine_errorColumns(t *testing.T) {
+	// columns count runes, so the é before the number is one column
+	line := Line{Num: 1, Text: "é 99999999999999999999"}
+	_, err := line.Ints()
+	var parseErr *Error
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("Ints() error = %v, want an Error", err)
+	}
+	if parseErr.Column != 3 {
+		t.Errorf("Ints() error column = %d, want 3", parseErr.Column)
+	}
+	if want := "é 99999999999999999999\n  ^"; parseErr.Pointer() != want {
+		t.Errorf("Pointer() = %q, want %q", parseErr.Pointer(), want)
+	}
+
+	type pair struct {
+		Name  string `parse:"name"`
+		Count int    `parse:"count"`
+	}
+	m, err := NewMatcher[pair](`^(?P<name>\S+) (?P<count>\S+)$`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = m.Match(Line{Num: 1, Text: "ééé x"})
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("Match() error = %v, want an Error", err)
+	}
+	if parseErr.Column != 5 {
+		t.Errorf("Match() error column = %d, want 5", parseErr.Column)
+	}
+}
+
+func TestError_Pointer(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		column int
+		want   string
+	}{
+		{"whole line", "abc", 0, "abc"},
+		{"first column", "abc", 1, "abc\n^"},
+		{"last column", "abc", 3, "abc\n  ^"},
+		{"past the end", "abc", 4, "abc\n   ^"},
+		{"tabs are kept", "\ta\tb", 4, "\ta\tb\n\t \t^"},
+		{"multibyte runes", "héllo", 3, "héllo\n  ^"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &Error{Line: 1, Column: tt.column, Text: tt.text}
+			if got := e.Pointer(); got != tt.want {
+				t.Errorf("Pointer() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatcher(t *testing.T) {
+	type fields struct {
+		Name   string  `parse:"name"`
+		Op     rune    `parse:"op,rune"`
+		Code   int32   `parse:"code"`
+		Count  uint8   `parse:"count"`
+		Weight float64 `parse:"weight"`
+		Note   string  `parse:"note"`
+		Other  string
+	}
+	m, err := NewMatcher[fields](`^(?P<name>\w+) (?P<op>\S+) (?P<code>\d+) (?P<count>\d+) (?P<weight>[\d.]+)(?: (?P<note>.+))?$`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		text    string
+		want    fields
+		wantErr bool
+	}{
+		{
+			name: "all fields",
+			text: "a + 42 7 1.5 hi there",
+			want: fields{Name: "a", Op: '+', Code: 42, Count: 7, Weight: 1.5, Note: "hi there"},
+		},
+		{
+			// only fields with the rune option take a character, other int32s are numbers
+			name: "single digit int32 is a number",
+			text: "a 4 4 7 1.5",
+			want: fields{Name: "a", Op: '4', Code: 4, Count: 7, Weight: 1.5},
+		},
+		{
+			name: "optional group that didn't match keeps the zero value",
+			text: "b é 10 0 2",
+			want: fields{Name: "b", Op: 'é', Code: 10, Weight: 2},
+		},
+		{name: "doesn't match", text: "a + 42", wantErr: true},
+		{name: "out of range", text: "a + 42 256 1.5", wantErr: true},
+		{name: "rune with more than one character", text: "a ++ 42 7 1.5", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := m.Match(Line{Num: 1, Text: tt.text})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Match() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want && !tt.wantErr {
+				t.Errorf("Match() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewMatcher_errors(t *testing.T) {
+	type missingGroup struct {
+		X int `parse:"x"`
+		Y int `parse:"y"`
+	}
+	if _, err := NewMatcher[missingGroup](`(?P<x>\d+),(\d+)`); err == nil {
+		t.Errorf("NewMatcher() should fail for a field without a group")
+	}
+
+	type unexported struct {
+		x int `parse:"x"`
+	}
+	if _, err := NewMatcher[unexported](`(?P<x>\d+)`); err == nil {
+		t.Errorf("NewMatcher() should fail for an unexported field")
+	}
+
+	type runeOption struct {
+		X int `parse:"x,rune"`
+	}
+	if _, err := NewMatcher[runeOption](`(?P<x>\d+)`); err == nil {
+		t.Errorf("NewMatcher() should fail for the rune option on a field that isn't a rune")
+	}
+
+	type unknownOption struct {
+		X rune `parse:"x,char"`
+	}
+	if _, err := NewMatcher[unknownOption](`(?P<x>\d+)`); err == nil {
+		t.Errorf("NewMatcher() should fail for an unknown option")
+	}
+
+	if _, err := NewMatcher[int](`(?P<x>\d+)`); err == nil {
+		t.Errorf("NewMatcher() should fail for a type that isn't a struct")
+	}
+
+	if _, err := NewMatcher[missingGroup](`(?P<x>\d+`); err == nil {
+		t.Errorf("NewMatcher() should fail for a bad regex")
+	}
+}
@@ -0,0 +1,187 @@
+package advent
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"runtime"
+	"sync"
+)
+
+// WorkerState is what a pool worker is doing with a job
+type WorkerState int
+
+const (
+	WorkerStarted WorkerState = iota
+	WorkerFinished
+	WorkerFailed
+)
+
+func (s WorkerState) String() string {
+	switch s {
+	case WorkerStarted:
+		return "started"
+	case WorkerFinished:
+		return "finished"
+	case WorkerFailed:
+		return "failed"
+	}
+	return fmt.Sprintf("WorkerState(%d)", int(s))
+}
+
+// WorkerStatus is sent to a pool's status callback when a worker starts or finishes a job
+type WorkerStatus struct {
+	Worker int
+	Job    int
+	State  WorkerState
+	Err    error
+}
+
+// PoolResult is the result of one job. Index is the job's index in the jobs slice.
+type PoolResult[J, R any] struct {
+	Index  int
+	Worker int
+	Job    J
+	Value  R
+	Err    error
+}
+
+// Pool runs a work function over a slice of jobs with a fixed number of workers
+type Pool[J, R any] struct {
+	workers  int
+	ordered  bool
+	onStatus func(status WorkerStatus)
+	work     func(ctx context.Context, worker int, job J) (R, error)
+}
+
+// NewPool creates a pool with a number of workers. 0 or less uses GOMAXPROCS workers.
+func NewPool[J, R any](workers int, work func(ctx context.Context, worker int, job J) (R, error)) *Pool[J, R] {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	return &Pool[J, R]{workers: workers, work: work}
+}
+
+// WithOrdered streams results in job order instead of as they finish
+func (p *Pool[J, R]) WithOrdered(ordered bool) *Pool[J, R] {
+	p.ordered = ordered
+	return p
+}
+
+// WithOnStatus calls onStatus from the worker goroutines as jobs start and finish
+func (p *Pool[J, R]) WithOnStatus(onStatus func(status WorkerStatus)) *Pool[J, R] {
+	p.onStatus = onStatus
+	return p
+}
+
+// Workers returns the number of workers in the pool
+func (p *Pool[J, R]) Workers() int {
+	return p.workers
+}
+
+// Stream runs the jobs and yields each result. Stopping early, or cancelling ctx, stops the workers
+// after their current job, and Stream waits for them so no status is sent after it returns. If ctx is cancelled before every job is done, the last result has
+// an Index of -1 and the context's error.
+func (p *Pool[J, R]) Stream(ctx context.Context, jobs []J) iter.Seq[PoolResult[J, R]] {
+	return func(yield func(PoolResult[J, R]) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		var wg sync.WaitGroup
+		defer func() {
+			cancel()
+			wg.Wait()
+		}()
+
+		indexes := make(chan int)
+		results := make(chan PoolResult[J, R], p.workers)
+
+		// feed the workers job indexes until we run out or are cancelled
+		go func() {
+			defer close(indexes)
+			for i := range jobs {
+				select {
+				case indexes <- i:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+
+		for worker := range p.workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range indexes {
+					p.status(WorkerStatus{Worker: worker, Job: i, State: WorkerStarted})
+					value, err := p.work(ctx, worker, jobs[i])
+					if err != nil {
+						p.status(WorkerStatus{Worker: worker, Job: i, State: WorkerFailed, Err: err})
+					} else {
+						p.status(WorkerStatus{Worker: worker, Job: i, State: WorkerFinished})
+					}
+
+					select {
+					case results <- PoolResult[J, R]{Index: i, Worker: worker, Job: jobs[i], Value: value, Err: err}:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}()
+		}
+
+		go func() {
+			wg.Wait()
+			close(results)
+		}()
+
+		yielded := 0
+		// ordered results wait here until the jobs before them are done
+		pending := make(map[int]PoolResult[J, R])
+		for result := range results {
+			if !p.ordered {
+				yielded++
+				if !yield(result) {
+					return
+				}
+				continue
+			}
+
+			pending[result.Index] = result
+			for {
+				next, ok := pending[yielded]
+				if !ok {
+					break
+				}
+				delete(pending, yielded)
+				yielded++
+				if !yield(next) {
+					return
+				}
+			}
+		}
+
+		if yielded < len(jobs) && ctx.Err() != nil {
+			yield(PoolResult[J, R]{Index: -1, Worker: -1, Err: ctx.Err()})
+		}
+	}
+}
+
+// Run runs all the jobs and returns their results in job order. It stops at the first error.
+func (p *Pool[J, R]) Run(ctx context.Context, jobs []J) ([]R, error) {
+	values := make([]R, len(jobs))
+	for result := range p.Stream(ctx, jobs) {
+		if result.Err != nil {
+			if result.Index == -1 {
+				return nil, result.Err
+			}
+			return nil, fmt.Errorf("job %d: %w", result.Index, result.Err)
+		}
+		values[result.Index] = result.Value
+	}
+	return values, nil
+}
+
+func (p *Pool[J, R]) status(status WorkerStatus) {
+	if p.onStatus != nil {
+		p.onStatus(status)
+	}
+}
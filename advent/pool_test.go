@@ -0,0 +1,130 @@
+package advent
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestPool_ordered(t *testing.T) {
+	jobs := []int{0, 1, 2, 3, 4, 5, 6, 7}
+	for _, ordered := range []bool{false, true} {
+		// job 0 waits for job 3, so results always come back out of order and ordered
+		// streams have to hold them until job 0 is done
+		job3Done := make(chan struct{})
+		pool := NewPool(4, func(ctx context.Context, worker int, job int) (int, error) {
+			switch job {
+			case 0:
+				<-job3Done
+			case 3:
+				close(job3Done)
+			}
+			return job * 10, nil
+		}).WithOrdered(ordered)
+
+		var indexes []int
+		for result := range pool.Stream(context.Background(), jobs) {
+			if result.Err != nil {
+				t.Fatal(result.Err)
+			}
+			if result.Value != result.Job*10 || result.Job != jobs[result.Index] {
+				t.Errorf("job %d at index %d has value %d", result.Job, result.Index, result.Value)
+			}
+			indexes = append(indexes, result.Index)
+		}
+
+		if len(indexes) != len(jobs) {
+			t.Fatalf("ordered=%v got %d results, want %d", ordered, len(indexes), len(jobs))
+		}
+		if sorted := slices.IsSorted(indexes); sorted != ordered {
+			t.Errorf("ordered=%v got results in order %v", ordered, indexes)
+		}
+	}
+}
+
+func TestPool_cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the first two jobs finish, the rest wait to be cancelled
+	jobs := make([]int, 100)
+	for i := range jobs {
+		jobs[i] = i
+	}
+	pool := NewPool(2, func(ctx context.Context, worker int, job int) (int, error) {
+		if job < 2 {
+			return job, nil
+		}
+		<-ctx.Done()
+		return 0, ctx.Err()
+	}).WithOrdered(true)
+
+	var results []PoolResult[int, int]
+	for result := range pool.Stream(ctx, jobs) {
+		results = append(results, result)
+		if len(results) == 2 {
+			cancel()
+		}
+	}
+
+	if len(results) < 3 || len(results) > len(jobs) {
+		t.Fatalf("got %d results after cancelling", len(results))
+	}
+	for _, result := range results[:2] {
+		if result.Err != nil {
+			t.Errorf("job %d failed before cancelling: %v", result.Index, result.Err)
+		}
+	}
+	last := results[len(results)-1]
+	if last.Index != -1 || !errors.Is(last.Err, context.Canceled) {
+		t.Errorf("last result = index %d, error %v, want index -1 and context.Canceled", last.Index, last.Err)
+	}
+}
+
+func TestPool_Run(t *testing.T) {
+	errBad := errors.New("bad job")
+	var mu sync.Mutex
+	states := map[WorkerState]int{}
+	pool := NewPool(0, func(ctx context.Context, worker int, job int) (int, error) {
+		if job < 0 {
+			return 0, errBad
+		}
+		return job * job, nil
+	}).WithOnStatus(func(status WorkerStatus) {
+		mu.Lock()
+		defer mu.Unlock()
+		states[status.State]++
+	})
+
+	if pool.Workers() < 1 {
+		t.Errorf("Workers() = %d, want GOMAXPROCS", pool.Workers())
+	}
+
+	// results are in job order even though the pool isn't ordered
+	got, err := pool.Run(context.Background(), []int{1, 2, 3, 4, 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{1, 4, 9, 16, 25}; !slices.Equal(got, want) {
+		t.Errorf("Run() = %v, want %v", got, want)
+	}
+	if states[WorkerStarted] != 5 || states[WorkerFinished] != 5 || states[WorkerFailed] != 0 {
+		t.Errorf("statuses = %v, want 5 started and finished", states)
+	}
+
+	_, err = pool.Run(context.Background(), []int{1, 2, -3, 4})
+	if !errors.Is(err, errBad) || err.Error() != "job 2: bad job" {
+		t.Errorf("Run() error = %v, want job 2: bad job", err)
+	}
+	if states[WorkerFailed] != 1 {
+		t.Errorf("statuses = %v, want 1 failed", states)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := pool.Run(ctx, []int{1, 2, 3}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Run() with a cancelled context error = %v, want context.Canceled", err)
+	}
+}
@@ -1,16 +1,21 @@
 package advent
 
-type position struct {
-	x int
-	y int
-}
+import (
+	"github.com/sirgwain/advent-of-code-2024/advent/geom"
+	"github.com/sirgwain/advent-of-code-2024/advent/tui"
+)
+
+// positions are geom.Points
+type position = geom.Point
 
 type positionDirection struct {
 	position
 	direction direction
 }
 
-func (p1 position) addDirection(dir direction) position {
-	x, y := dir.offsetMultiplier()
-	return position{p1.x + x, p1.y + y}
+// inspector adapts a solver's position inspect function to a tui.Inspector
+func inspector(inspect func(p position) string) tui.Inspector {
+	return func(x, y int) string {
+		return inspect(position{X: x, Y: y})
+	}
 }
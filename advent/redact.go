@@ -0,0 +1,96 @@
+package advent
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// RedactPolicy decides when a solution shown in a visualization is hidden
+type RedactPolicy int
+
+const (
+	// RedactRatio hides the solution once it's past RedactRatio of the final answer
+	RedactRatio RedactPolicy = iota
+	// RedactDistance hides the solution once it's within RedactDistance of the final answer
+	RedactDistance
+	// RedactFinal only hides the final answer
+	RedactFinal
+	// RedactAlways hides the solution for the whole run
+	RedactAlways
+)
+
+// ParseRedactPolicy converts a flag value into a RedactPolicy
+func ParseRedactPolicy(s string) (RedactPolicy, error) {
+	switch s {
+	case "", "ratio":
+		return RedactRatio, nil
+	case "distance":
+		return RedactDistance, nil
+	case "final", "final-only":
+		return RedactFinal, nil
+	case "always":
+		return RedactAlways, nil
+	}
+	return RedactRatio, fmt.Errorf("unknown redact policy %s, expected one of always, ratio, distance, final", s)
+}
+
+// unknownSolution is passed as the final answer when a visualization doesn't know it ahead of time.
+// Redacted solutions are always hidden if the answer is unknown.
+const unknownSolution = -1
+
+// redaction hides solutions in visualizations so they can be recorded without giving away the answer
+type redaction struct {
+	enabled  bool
+	policy   RedactPolicy
+	ratio    float64
+	distance int
+}
+
+// noRedaction shows every solution, for regular runs and output after a visualization exits
+var noRedaction = redaction{}
+
+// redaction returns the redaction for a run's options
+func (o *Options) redaction() redaction {
+	return redaction{
+		enabled:  o.RedactSolution,
+		policy:   o.RedactPolicy,
+		ratio:    o.RedactRatio,
+		distance: o.RedactDistance,
+	}
+}
+
+// hide returns true if value, on its way to the final solution, should be hidden
+func (r redaction) hide(value, solution int) bool {
+	if !r.enabled {
+		return false
+	}
+	if solution == unknownSolution {
+		return true
+	}
+
+	switch r.policy {
+	case RedactRatio:
+		return solution == 0 || float64(value)/float64(solution) > r.ratio
+	case RedactDistance:
+		return solution-value < r.distance
+	case RedactFinal:
+		return value == solution
+	}
+	return true
+}
+
+// renderText renders a solution that isn't an int, like a big sum. It's always hidden when redacting.
+func (r redaction) renderText(text string) string {
+	if r.enabled {
+		return solutionStyle.Render("<redacted>")
+	}
+	return solutionStyle.Render(text)
+}
+
+// render renders a solution value, or <redacted> if it should be hidden
+func (r redaction) render(value, solution int) string {
+	if r.hide(value, solution) {
+		return solutionStyle.Render("<redacted>")
+	}
+	return solutionStyle.Render(strconv.Itoa(value))
+}
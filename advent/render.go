@@ -0,0 +1,142 @@
+package advent
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sirgwain/advent-of-code-2024/advent/tui"
+)
+
+// BoardRenderer draws a board of values as styled text. Each value is rendered with its style once
+// and the result is reused for every cell and frame after that, so large boards don't create a new
+// style per cell. The same renderer produces tui cells, so a board's plain text view and its
+// visualization always match.
+type BoardRenderer[T comparable] struct {
+	text  func(v T) string
+	style func(v T) (lipgloss.Style, bool)
+
+	// renders caches the rendered text for each value. Visualizations render from the solver
+	// goroutines, so it's guarded by a mutex.
+	mu      sync.RWMutex
+	renders map[T]string
+}
+
+// Layer draws another renderer over a board at the positions it contains, like visited squares or a path
+type Layer[T comparable] struct {
+	contains func(p position) bool
+	renderer *BoardRenderer[T]
+}
+
+// NewBoardRenderer creates a renderer that draws each value as text, unstyled until styles are added
+func NewBoardRenderer[T comparable](text func(v T) string) *BoardRenderer[T] {
+	return &BoardRenderer[T]{
+		text:    text,
+		style:   func(v T) (lipgloss.Style, bool) { return lipgloss.Style{}, false },
+		renders: map[T]string{},
+	}
+}
+
+// NewRuneRenderer creates a renderer for rune boards that draws each rune as itself
+func NewRuneRenderer() *BoardRenderer[rune] {
+	return NewBoardRenderer(func(r rune) string { return string(r) })
+}
+
+// WithStyles styles values found in styles. Anything else is drawn as plain text.
+func (r *BoardRenderer[T]) WithStyles(styles map[T]lipgloss.Style) *BoardRenderer[T] {
+	return r.WithStyleFunc(func(v T) (lipgloss.Style, bool) {
+		style, ok := styles[v]
+		return style, ok
+	})
+}
+
+// WithStyleFunc styles values with a func, for boards with too many values for a map, like heights
+// or plot types. Values are drawn as plain text when the func returns false.
+func (r *BoardRenderer[T]) WithStyleFunc(style func(v T) (lipgloss.Style, bool)) *BoardRenderer[T] {
+	r.style = style
+	r.Reset()
+	return r
+}
+
+// Reset clears the cached renders
+func (r *BoardRenderer[T]) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	clear(r.renders)
+}
+
+// Over returns a layer that draws this renderer over a board wherever contains is true
+func (r *BoardRenderer[T]) Over(contains func(p position) bool) Layer[T] {
+	return Layer[T]{contains: contains, renderer: r}
+}
+
+// RenderValue renders a single value
+func (r *BoardRenderer[T]) RenderValue(v T) string {
+	r.mu.RLock()
+	s, ok := r.renders[v]
+	r.mu.RUnlock()
+	if ok {
+		return s
+	}
+
+	s = r.text(v)
+	if style, ok := r.style(v); ok {
+		s = style.Render(s)
+	}
+
+	r.mu.Lock()
+	r.renders[v] = s
+	r.mu.Unlock()
+	return s
+}
+
+// RenderCell renders the value at p, drawn by the last layer that contains p
+func (r *BoardRenderer[T]) RenderCell(p position, v T, layers ...Layer[T]) string {
+	return r.layer(p, layers).RenderValue(v)
+}
+
+// Cell converts the value at p into a tui cell with the colors of its style
+func (r *BoardRenderer[T]) Cell(p position, v T, layers ...Layer[T]) tui.Cell {
+	renderer := r.layer(p, layers)
+	var char rune
+	for _, c := range renderer.text(v) {
+		char = c
+		break
+	}
+
+	cell := tui.Cell{Char: char}
+	if style, ok := renderer.style(v); ok {
+		cell.Fg = style.GetForeground()
+		cell.Bg = style.GetBackground()
+	}
+	return cell
+}
+
+// Render draws a grid, one line per row
+func (r *BoardRenderer[T]) Render(g *Grid[T], layers ...Layer[T]) string {
+	return g.Render(func(p position, v T) string {
+		return r.RenderCell(p, v, layers...)
+	})
+}
+
+// RenderRows draws a 2D slice, one line per row
+func (r *BoardRenderer[T]) RenderRows(rows [][]T, layers ...Layer[T]) string {
+	var sb strings.Builder
+	for y, row := range rows {
+		for x, v := range row {
+			sb.WriteString(r.RenderCell(position{X: x, Y: y}, v, layers...))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// layer returns the renderer for the last layer that contains p, or r if none do
+func (r *BoardRenderer[T]) layer(p position, layers []Layer[T]) *BoardRenderer[T] {
+	for i := len(layers) - 1; i >= 0; i-- {
+		if layers[i].contains(p) {
+			return layers[i].renderer
+		}
+	}
+	return r
+}
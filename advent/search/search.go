@@ -0,0 +1,290 @@
+// Package search finds shortest paths through any graph described by a neighbors function,
+// like positions on a grid or richer states like a position and facing direction.
+package search
+
+import (
+	"container/heap"
+	"iter"
+)
+
+// Edge is a move from one state to another and what it costs
+type Edge[S comparable] struct {
+	To   S
+	Cost int
+}
+
+// Result holds what a search found. Costs and Preds cover every state the search reached,
+// so a search without a goal can be used to find the distance to everything.
+type Result[S comparable] struct {
+	Start S
+	// Goal is the first goal state reached, if Found
+	Goal  S
+	Found bool
+	// Cost is the cost from Start to Goal
+	Cost int
+	// Path is one optimal path from Start to Goal, including both
+	Path []S
+	// Costs are the lowest costs from Start to each reached state
+	Costs map[S]int
+	// Preds are all the states that lead to a state at its lowest cost
+	Preds map[S][]S
+
+	pathCounts map[S]int
+}
+
+type options[S comparable] struct {
+	onVisit   func(s S, cost int)
+	heuristic func(s S) int
+}
+
+// Option configures a search
+type Option[S comparable] func(*options[S])
+
+// WithOnVisit calls onVisit each time a state is taken off the frontier, so visualizations can
+// animate the search
+func WithOnVisit[S comparable](onVisit func(s S, cost int)) Option[S] {
+	return func(o *options[S]) {
+		o.onVisit = onVisit
+	}
+}
+
+// BFS searches a graph where every move costs 1. If goal is nil, the whole reachable graph is searched.
+func BFS[S comparable](start S, neighbors func(s S) []S, goal func(s S) bool, opts ...Option[S]) *Result[S] {
+	o := newOptions(opts...)
+	r := newResult(start)
+
+	queue := []S{start}
+	for len(queue) > 0 {
+		s := queue[0]
+		queue = queue[1:]
+		cost := r.Costs[s]
+		if r.Found && cost >= r.Cost {
+			// everything left is at least as far as the goal, so all its predecessors are known
+			break
+		}
+		if o.onVisit != nil {
+			o.onVisit(s, cost)
+		}
+		if goal != nil && goal(s) {
+			r.found(s, cost)
+			continue
+		}
+
+		for _, next := range neighbors(s) {
+			if r.relax(s, next, cost+1) {
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	r.buildPath()
+	return r
+}
+
+// Dijkstra searches a graph with non-negative move costs. If goal is nil, the whole reachable graph is searched.
+// Moves can cost 0, even in a cycle. A state's cost is final once it's visited, and only states visited
+// before it are recorded as its Preds, so Preds never loops back on itself.
+func Dijkstra[S comparable](start S, neighbors func(s S) []Edge[S], goal func(s S) bool, opts ...Option[S]) *Result[S] {
+	return weighted(start, neighbors, goal, newOptions(opts...))
+}
+
+// AStar is Dijkstra guided by a heuristic, the estimated cost from a state to the goal. The heuristic
+// must never overestimate the cost, and must be consistent for Preds to hold every optimal predecessor.
+func AStar[S comparable](start S, neighbors func(s S) []Edge[S], goal func(s S) bool, heuristic func(s S) int, opts ...Option[S]) *Result[S] {
+	o := newOptions(opts...)
+	o.heuristic = heuristic
+	return weighted(start, neighbors, goal, o)
+}
+
+func weighted[S comparable](start S, neighbors func(s S) []Edge[S], goal func(s S) bool, o options[S]) *Result[S] {
+	r := newResult(start)
+	visited := map[S]bool{}
+
+	frontier := &queue[S]{}
+	heap.Push(frontier, item[S]{state: start, priority: o.estimate(start, 0)})
+	for frontier.Len() > 0 {
+		current := heap.Pop(frontier).(item[S])
+		s := current.state
+		cost := r.Costs[s]
+		if current.priority > o.estimate(s, cost) {
+			// we found a cheaper way here after this was queued
+			continue
+		}
+		if r.Found && current.priority > r.Cost {
+			break
+		}
+		visited[s] = true
+		if o.onVisit != nil {
+			o.onVisit(s, cost)
+		}
+		if goal != nil && goal(s) {
+			r.found(s, cost)
+			continue
+		}
+
+		for _, edge := range neighbors(s) {
+			if visited[edge.To] {
+				// a tie through a free move back to a visited state would make Preds a cycle
+				continue
+			}
+			if r.relax(s, edge.To, cost+edge.Cost) {
+				heap.Push(frontier, item[S]{state: edge.To, priority: o.estimate(edge.To, cost+edge.Cost)})
+			}
+		}
+	}
+
+	r.buildPath()
+	return r
+}
+
+func newOptions[S comparable](opts ...Option[S]) options[S] {
+	var o options[S]
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// estimate is the priority of a state on the frontier
+func (o options[S]) estimate(s S, cost int) int {
+	if o.heuristic == nil {
+		return cost
+	}
+	return cost + o.heuristic(s)
+}
+
+func newResult[S comparable](start S) *Result[S] {
+	return &Result[S]{
+		Start: start,
+		Costs: map[S]int{start: 0},
+		Preds: map[S][]S{},
+	}
+}
+
+// relax records from as a predecessor of to if cost is as cheap as any way to reach to so far.
+// It returns true if to should be added to the frontier.
+func (r *Result[S]) relax(from, to S, cost int) bool {
+	best, reached := r.Costs[to]
+	switch {
+	case !reached || cost < best:
+		r.Costs[to] = cost
+		r.Preds[to] = []S{from}
+		return true
+	case cost == best:
+		r.Preds[to] = append(r.Preds[to], from)
+	}
+	return false
+}
+
+func (r *Result[S]) found(s S, cost int) {
+	if !r.Found {
+		r.Found = true
+		r.Goal = s
+		r.Cost = cost
+	}
+}
+
+func (r *Result[S]) buildPath() {
+	if !r.Found {
+		return
+	}
+	r.Path = r.PathTo(r.Goal)
+}
+
+// PathTo returns one optimal path from Start to s, or nil if s wasn't reached
+func (r *Result[S]) PathTo(s S) []S {
+	if _, ok := r.Costs[s]; !ok {
+		return nil
+	}
+	path := []S{s}
+	for s != r.Start {
+		s = r.Preds[s][0]
+		path = append(path, s)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// Paths iterates over every optimal path from Start to s
+func (r *Result[S]) Paths(s S) iter.Seq[[]S] {
+	return func(yield func([]S) bool) {
+		if _, ok := r.Costs[s]; !ok {
+			return
+		}
+		var walk func(s S, suffix []S) bool
+		walk = func(s S, suffix []S) bool {
+			suffix = append([]S{s}, suffix...)
+			if s == r.Start {
+				return yield(suffix)
+			}
+			for _, pred := range r.Preds[s] {
+				if !walk(pred, suffix) {
+					return false
+				}
+			}
+			return true
+		}
+		walk(s, nil)
+	}
+}
+
+// CountPaths returns the number of optimal paths from Start to s
+func (r *Result[S]) CountPaths(s S) int {
+	if _, ok := r.Costs[s]; !ok {
+		return 0
+	}
+	if r.pathCounts == nil {
+		r.pathCounts = map[S]int{r.Start: 1}
+	}
+	if count, ok := r.pathCounts[s]; ok {
+		return count
+	}
+	count := 0
+	for _, pred := range r.Preds[s] {
+		count += r.CountPaths(pred)
+	}
+	r.pathCounts[s] = count
+	return count
+}
+
+// OnOptimalPath returns every state on any optimal path from Start to s
+func (r *Result[S]) OnOptimalPath(s S) map[S]bool {
+	states := map[S]bool{}
+	if _, ok := r.Costs[s]; !ok {
+		return states
+	}
+	pending := []S{s}
+	for len(pending) > 0 {
+		current := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if states[current] {
+			continue
+		}
+		states[current] = true
+		pending = append(pending, r.Preds[current]...)
+	}
+	return states
+}
+
+// item is a state on the frontier of a weighted search
+type item[S comparable] struct {
+	state    S
+	priority int
+}
+
+// queue is a min heap of items by priority
+type queue[S comparable] []item[S]
+
+func (q queue[S]) Len() int           { return len(q) }
+func (q queue[S]) Less(i, j int) bool { return q[i].priority < q[j].priority }
+func (q queue[S]) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *queue[S]) Push(x any)        { *q = append(*q, x.(item[S])) }
+func (q *queue[S]) Pop() any {
+	old := *q
+	n := len(old)
+	it := old[n-1]
+	*q = old[:n-1]
+	return it
+}
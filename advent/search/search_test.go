@@ -0,0 +1,124 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/sirgwain/advent-of-code-2024/advent/geom"
+)
+
+// a 5x5 open grid with a wall down the middle column, leaving a gap at the bottom
+func gridEdges(p geom.Point) []Edge[geom.Point] {
+	var edges []Edge[geom.Point]
+	for _, dir := range geom.Cardinal {
+		to := p.Move(dir)
+		if to.X < 0 || to.X >= 5 || to.Y < 0 || to.Y >= 5 || (to.X == 2 && to.Y < 4) {
+			continue
+		}
+		edges = append(edges, Edge[geom.Point]{To: to, Cost: 1})
+	}
+	return edges
+}
+
+func TestSearch(t *testing.T) {
+	start, end := geom.Point{X: 0, Y: 0}, geom.Point{X: 4, Y: 0}
+	goal := func(p geom.Point) bool { return p == end }
+	neighbors := func(p geom.Point) []geom.Point {
+		var points []geom.Point
+		for _, edge := range gridEdges(p) {
+			points = append(points, edge.To)
+		}
+		return points
+	}
+
+	tests := []struct {
+		name   string
+		result *Result[geom.Point]
+	}{
+		{"BFS", BFS(start, neighbors, goal)},
+		{"Dijkstra", Dijkstra(start, gridEdges, goal)},
+		{"AStar", AStar(start, gridEdges, goal, end.Manhattan)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := tt.result
+			if !r.Found || r.Cost != 12 {
+				t.Fatalf("Found = %v, Cost = %d, want true, 12", r.Found, r.Cost)
+			}
+			if len(r.Path) != 13 || r.Path[0] != start || r.Path[12] != end {
+				t.Errorf("Path = %v, want 13 steps from %v to %v", r.Path, start, end)
+			}
+			// 5 ways down the left two columns to the gap, and 5 ways up the right two columns
+			if got := r.CountPaths(end); got != 25 {
+				t.Errorf("CountPaths() = %d, want 25", got)
+			}
+		})
+	}
+}
+
+func TestCountPaths(t *testing.T) {
+	// every monotonic path across a 3x3 grid, 6 of them
+	neighbors := func(p geom.Point) []geom.Point {
+		var points []geom.Point
+		if p.X < 2 {
+			points = append(points, p.Move(geom.Right))
+		}
+		if p.Y < 2 {
+			points = append(points, p.Move(geom.Down))
+		}
+		return points
+	}
+	visits := 0
+	r := BFS(geom.Point{}, neighbors, nil, WithOnVisit(func(p geom.Point, cost int) { visits++ }))
+	end := geom.Point{X: 2, Y: 2}
+
+	if got := r.CountPaths(end); got != 6 {
+		t.Errorf("CountPaths() = %d, want 6", got)
+	}
+	paths := 0
+	for range r.Paths(end) {
+		paths++
+	}
+	if paths != 6 {
+		t.Errorf("Paths() yielded %d paths, want 6", paths)
+	}
+	if visits != 9 {
+		t.Errorf("visited %d states, want 9", visits)
+	}
+	if got := len(r.OnOptimalPath(end)); got != 9 {
+		t.Errorf("OnOptimalPath() has %d states, want 9", got)
+	}
+}
+
+func TestDijkstra_zeroCost(t *testing.T) {
+	// a and b are connected both ways for free, and both lead to c
+	edges := map[string][]Edge[string]{
+		"a": {{To: "b", Cost: 0}, {To: "c", Cost: 1}},
+		"b": {{To: "a", Cost: 0}, {To: "b", Cost: 0}, {To: "c", Cost: 1}},
+	}
+	neighbors := func(s string) []Edge[string] { return edges[s] }
+
+	for _, r := range []*Result[string]{
+		Dijkstra("a", neighbors, nil),
+		AStar("a", neighbors, func(s string) bool { return s == "c" }, func(s string) int { return 0 }),
+	} {
+		if r.Costs["b"] != 0 || r.Costs["c"] != 1 {
+			t.Errorf("Costs = %v, want b 0 and c 1", r.Costs)
+		}
+		if got := r.CountPaths("c"); got != 2 {
+			t.Errorf("CountPaths(c) = %d, want 2", got)
+		}
+		paths := 0
+		for path := range r.Paths("c") {
+			if len(path) > 3 {
+				t.Errorf("Paths(c) yielded %v, which loops", path)
+			}
+			paths++
+		}
+		if paths != 2 {
+			t.Errorf("Paths(c) yielded %d paths, want 2", paths)
+		}
+		if got := len(r.OnOptimalPath("c")); got != 3 {
+			t.Errorf("OnOptimalPath(c) has %d states, want 3", got)
+		}
+	}
+}
@@ -15,7 +15,7 @@ func duplicate2DSlice[T any](original [][]T) [][]T {
 	return duplicate
 }
 
-func make2DSlice[T uint](width, height int) [][]T {
+func make2DSlice[T any](width, height int) [][]T {
 	s := make([][]T, height)
 	for i := range height {
 		s[i] = make([]T, width)
@@ -36,14 +36,3 @@ func removeIndex(original []int, index int) []int {
 	newSlice = append(newSlice, original[index+1:]...)
 	return newSlice
 }
-
-// getChar returns a char at x,y in the input or "" if no char is present
-func getChar(x, y int, input []string) string {
-	if y < 0 || y >= len(input) {
-		return ""
-	}
-	if x < 0 || x >= len(input[y]) {
-		return ""
-	}
-	return string(input[y][x])
-}
@@ -0,0 +1,145 @@
+package advent
+
+import (
+	"cmp"
+	"iter"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// SparseGrid is an unbounded grid that only stores the positions that are set, for puzzles
+// that are lists of coordinates. It tracks the bounding box of everything set.
+type SparseGrid[T comparable] struct {
+	cells map[position]T
+	min   position
+	max   position
+	// bounds need to be recalculated after a delete
+	dirty bool
+}
+
+func NewSparseGrid[T comparable]() *SparseGrid[T] {
+	return &SparseGrid[T]{cells: map[position]T{}}
+}
+
+// SparseGridFromGrid copies every position of a grid that isn't empty
+func SparseGridFromGrid[T comparable](g *Grid[T], empty T) *SparseGrid[T] {
+	s := NewSparseGrid[T]()
+	for p, v := range g.All() {
+		if v != empty {
+			s.Set(p, v)
+		}
+	}
+	return s
+}
+
+// Len is the number of positions set
+func (s *SparseGrid[T]) Len() int {
+	return len(s.cells)
+}
+
+// Get returns the value at p, or the zero value if p isn't set
+func (s *SparseGrid[T]) Get(p position) T {
+	return s.cells[p]
+}
+
+// Lookup returns the value at p and whether it's set
+func (s *SparseGrid[T]) Lookup(p position) (T, bool) {
+	v, ok := s.cells[p]
+	return v, ok
+}
+
+// Set sets the value at p, growing the bounding box if needed
+func (s *SparseGrid[T]) Set(p position, v T) {
+	if len(s.cells) == 0 && !s.dirty {
+		s.min, s.max = p, p
+	} else if !s.dirty {
+		s.min = position{X: min(s.min.X, p.X), Y: min(s.min.Y, p.Y)}
+		s.max = position{X: max(s.max.X, p.X), Y: max(s.max.Y, p.Y)}
+	}
+	s.cells[p] = v
+}
+
+// Update sets the value at p to the result of update, called with the current value or the zero value.
+// Use it to keep counts, like the number of robots at a position.
+func (s *SparseGrid[T]) Update(p position, update func(v T) T) {
+	s.Set(p, update(s.cells[p]))
+}
+
+// Delete unsets p
+func (s *SparseGrid[T]) Delete(p position) {
+	if _, ok := s.cells[p]; ok {
+		delete(s.cells, p)
+		s.dirty = true
+	}
+}
+
+// Bounds returns the top left and bottom right of the bounding box of all set positions,
+// or false if nothing is set
+func (s *SparseGrid[T]) Bounds() (minimum, maximum position, ok bool) {
+	if len(s.cells) == 0 {
+		return position{}, position{}, false
+	}
+	if s.dirty {
+		first := true
+		for p := range s.cells {
+			if first {
+				s.min, s.max = p, p
+				first = false
+				continue
+			}
+			s.min = position{X: min(s.min.X, p.X), Y: min(s.min.Y, p.Y)}
+			s.max = position{X: max(s.max.X, p.X), Y: max(s.max.Y, p.Y)}
+		}
+		s.dirty = false
+	}
+	return s.min, s.max, true
+}
+
+// All iterates over the set positions in row order, top to bottom and left to right
+func (s *SparseGrid[T]) All() iter.Seq2[position, T] {
+	return func(yield func(position, T) bool) {
+		positions := slices.SortedFunc(maps.Keys(s.cells), func(a, b position) int {
+			return cmp.Or(cmp.Compare(a.Y, b.Y), cmp.Compare(a.X, b.X))
+		})
+		for _, p := range positions {
+			if !yield(p, s.cells[p]) {
+				return
+			}
+		}
+	}
+}
+
+// Grid copies the rectangle from minimum to maximum, inclusive, into a dense grid.
+// Position minimum in the sparse grid is 0,0 in the dense grid.
+func (s *SparseGrid[T]) Grid(minimum, maximum position) *Grid[T] {
+	g := NewGrid[T](maximum.X-minimum.X+1, maximum.Y-minimum.Y+1)
+	for p, v := range s.cells {
+		g.Set(p.Sub(minimum), v)
+	}
+	return g
+}
+
+// Render draws the bounding box of the set positions
+func (s *SparseGrid[T]) Render(render func(p position, v T, ok bool) string) string {
+	minimum, maximum, ok := s.Bounds()
+	if !ok {
+		return ""
+	}
+	return s.RenderRect(minimum, maximum, render)
+}
+
+// RenderRect draws the rectangle from minimum to maximum, inclusive, calling render for every
+// position whether it's set or not
+func (s *SparseGrid[T]) RenderRect(minimum, maximum position, render func(p position, v T, ok bool) string) string {
+	var sb strings.Builder
+	for y := minimum.Y; y <= maximum.Y; y++ {
+		for x := minimum.X; x <= maximum.X; x++ {
+			p := position{X: x, Y: y}
+			v, ok := s.cells[p]
+			sb.WriteString(render(p, v, ok))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,120 @@
+package advent
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSparseGrid_Bounds(t *testing.T) {
+	s := NewSparseGrid[int]()
+	if _, _, ok := s.Bounds(); ok {
+		t.Fatal("empty grid should have no bounds")
+	}
+
+	s.Set(position{X: 2, Y: 3}, 1)
+	s.Set(position{X: -1, Y: 5}, 2)
+	s.Set(position{X: 4, Y: -2}, 3)
+	checkBounds := func(name string, wantMin, wantMax position) {
+		t.Helper()
+		minimum, maximum, ok := s.Bounds()
+		if !ok || minimum != wantMin || maximum != wantMax {
+			t.Errorf("%s: Bounds() = %v, %v, %v, want %v, %v", name, minimum, maximum, ok, wantMin, wantMax)
+		}
+	}
+	checkBounds("after set", position{X: -1, Y: -2}, position{X: 4, Y: 5})
+
+	// deleting an edge shrinks the bounds, and sets while dirty are included when they're recalculated
+	s.Delete(position{X: 4, Y: -2})
+	s.Set(position{X: 0, Y: 7}, 4)
+	checkBounds("after delete", position{X: -1, Y: 3}, position{X: 2, Y: 7})
+
+	// sets after the recalculation grow the bounds again
+	s.Set(position{X: 10, Y: 0}, 5)
+	checkBounds("after set again", position{X: -1, Y: 0}, position{X: 10, Y: 7})
+
+	// deleting a position that isn't set changes nothing
+	s.Delete(position{X: 100, Y: 100})
+	checkBounds("after deleting nothing", position{X: -1, Y: 0}, position{X: 10, Y: 7})
+
+	for p := range s.All() {
+		s.Delete(p)
+	}
+	if _, _, ok := s.Bounds(); ok || s.Len() != 0 {
+		t.Errorf("grid should be empty after deleting everything, has %d", s.Len())
+	}
+
+	// the first set after emptying starts the bounds over
+	s.Set(position{X: 8, Y: 8}, 6)
+	checkBounds("after emptying", position{X: 8, Y: 8}, position{X: 8, Y: 8})
+}
+
+func TestSparseGrid_All(t *testing.T) {
+	s := NewSparseGrid[int]()
+	s.Set(position{X: 3, Y: 1}, 1)
+	s.Set(position{X: 0, Y: 2}, 2)
+	s.Set(position{X: 1, Y: 1}, 3)
+	s.Set(position{X: 5, Y: -1}, 4)
+	s.Update(position{X: 1, Y: 1}, func(v int) int { return v + 10 })
+
+	want := []struct {
+		p position
+		v int
+	}{
+		{position{X: 5, Y: -1}, 4},
+		{position{X: 1, Y: 1}, 13},
+		{position{X: 3, Y: 1}, 1},
+		{position{X: 0, Y: 2}, 2},
+	}
+	i := 0
+	for p, v := range s.All() {
+		if i >= len(want) || p != want[i].p || v != want[i].v {
+			t.Fatalf("All() item %d = %v %d", i, p, v)
+		}
+		i++
+	}
+	if i != len(want) {
+		t.Errorf("All() returned %d items, want %d", i, len(want))
+	}
+}
+
+func TestSparseGrid_roundTrip(t *testing.T) {
+	g, err := ParseGrid(strings.NewReader("..#.\n#...\n...#"), parseRune)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := SparseGridFromGrid(g, '.')
+	if s.Len() != 3 {
+		t.Errorf("SparseGridFromGrid() set %d positions, want 3", s.Len())
+	}
+	if _, ok := s.Lookup(position{X: 1, Y: 0}); ok {
+		t.Errorf("empty positions shouldn't be set")
+	}
+
+	// copying the whole rectangle back gives the same grid, with the zero value for empty positions
+	width, height := g.Size()
+	dense := s.Grid(position{}, position{X: width - 1, Y: height - 1})
+	got := dense.Render(func(p position, v rune) string {
+		if v == 0 {
+			return "."
+		}
+		return string(v)
+	})
+	if got != g.String() {
+		t.Errorf("Grid() got\n%s\nwant\n%s", got, g)
+	}
+
+	// a smaller rectangle is offset to its top left, and drops what's outside it
+	render := func(p position, v rune, ok bool) string {
+		if !ok {
+			return "."
+		}
+		return string(v)
+	}
+	if got, want := s.Grid(position{X: 2, Y: 0}, position{X: 3, Y: 2}).String(), "#\x00\n\x00\x00\n\x00#\n"; got != want {
+		t.Errorf("Grid() of a smaller rectangle = %q, want %q", got, want)
+	}
+	if got, want := s.Render(render), "..#.\n#...\n...#\n"; got != want {
+		t.Errorf("Render() got\n%s\nwant\n%s", got, want)
+	}
+}
@@ -1,36 +1,211 @@
 package advent
 
 import (
+	"strconv"
+
 	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 	"github.com/sirgwain/advent-of-code-2024/advent/color"
 )
 
+// theme is the active color theme, all styles below are built from it
+var theme color.Theme
+
 var (
 	// the style of the solution text
-	solutionStyle        = lipgloss.NewStyle().Foreground(lipgloss.Color("86"))
-	correctResultStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("40"))
-	incorrectResultStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+	solutionStyle        lipgloss.Style
+	correctResultStyle   lipgloss.Style
+	incorrectResultStyle lipgloss.Style
+
+	// day1 lists
+	sortedStyle   lipgloss.Style
+	unsortedStyle lipgloss.Style
+	insertedStyle lipgloss.Style
+	barStyle      lipgloss.Style
+
+	// day3 memory
+	memoryStyle    lipgloss.Style
+	executedStyle  lipgloss.Style
+	skippedStyle   lipgloss.Style
+	conditionStyle lipgloss.Style
+	currentStyle   lipgloss.Style
+
+	// day4 word search
+	letterStyle lipgloss.Style
+	foundStyle  lipgloss.Style
 
 	// day6 map
-	guardStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("211"))
-	obstacleStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("82"))
-	pathStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("202"))
+	guardStyle    lipgloss.Style
+	obstacleStyle lipgloss.Style
+	pathStyle     lipgloss.Style
 
 	// day7 numbers and operators
-	numberStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("211"))
-	operatorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("82"))
+	numberStyle   lipgloss.Style
+	operatorStyle lipgloss.Style
 
 	// day8 map
-	antennaStyle             = lipgloss.NewStyle().Foreground(lipgloss.Color("82"))
-	antennaWithAntinodeStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("87"))
-	antinodeStyle            = lipgloss.NewStyle().Foreground(lipgloss.Color("202"))
+	antennaStyle             lipgloss.Style
+	antennaWithAntinodeStyle lipgloss.Style
+	antinodeStyle            lipgloss.Style
 
 	// day14
-	robotGreenStyle = lipgloss.NewStyle().Foreground(color.BrightRed)
-	robotRedStyle   = lipgloss.NewStyle().Foreground(color.MediumGreen)
+	robotSingleStyle  lipgloss.Style
+	robotStackedStyle lipgloss.Style
+	midStyle          lipgloss.Style
 
 	// day15
-	robotStyle = lipgloss.NewStyle().Foreground(color.BrightCyan)
-	wallStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("202"))
-	boxStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("82"))
+	robotStyle lipgloss.Style
+	wallStyle  lipgloss.Style
+	boxStyle   lipgloss.Style
+)
+
+// prerender some styled characters
+var (
+	// day10 heights, one slice for unvisited and one for visited positions
+	heightRenders        []string
+	heightVisitedRenders []string
+
+	// day14
+	robotSingleRender  string
+	robotStackedRender string
+	midRender          string
 )
+
+// board renderers, they cache each styled value so boards can be redrawn every frame
+var (
+	// day4 letters, the found layer is drawn over matched letters
+	day4Renderer      *BoardRenderer[rune]
+	day4FoundRenderer *BoardRenderer[rune]
+
+	// day6 map
+	day6Renderer *BoardRenderer[rune]
+
+	// day8 map, the antinode layer is drawn over positions with antinodes
+	day8Renderer         *BoardRenderer[rune]
+	day8AntinodeRenderer *BoardRenderer[rune]
+
+	// day12 plots
+	day12Renderer *BoardRenderer[day12Plot]
+
+	// day15 warehouse
+	day15Renderer *BoardRenderer[rune]
+)
+
+func init() {
+	applyTheme(color.Dark)
+}
+
+// SetTheme switches all styles to a built in theme. The plain theme is used instead if
+// NO_COLOR is set or the terminal doesn't support colors
+func SetTheme(name string) error {
+	t, err := color.FindTheme(name)
+	if err != nil {
+		return err
+	}
+	if lipgloss.ColorProfile() == termenv.Ascii {
+		t = color.Plain
+	}
+	applyTheme(t)
+	return nil
+}
+
+// applyTheme maps each visual role to a theme color and prerenders styled characters
+func applyTheme(t color.Theme) {
+	theme = t
+
+	solutionStyle = fg(t.Solution)
+	correctResultStyle = fg(t.Success)
+	incorrectResultStyle = fg(t.Error)
+
+	sortedStyle = fg(t.Primary)
+	unsortedStyle = fg(t.Muted)
+	insertedStyle = fg(t.Accent)
+	barStyle = fg(t.Secondary)
+
+	memoryStyle = fg(t.Muted)
+	executedStyle = fg(t.Success)
+	skippedStyle = fg(t.Error)
+	conditionStyle = fg(t.Primary)
+	currentStyle = fg(t.Highlight).Background(t.Background)
+
+	letterStyle = fg(t.Muted)
+	foundStyle = fg(t.Highlight)
+
+	guardStyle = fg(t.Highlight)
+	obstacleStyle = fg(t.Primary)
+	pathStyle = fg(t.Secondary)
+
+	numberStyle = fg(t.Highlight)
+	operatorStyle = fg(t.Primary)
+
+	antennaStyle = fg(t.Primary)
+	antennaWithAntinodeStyle = fg(t.Accent)
+	antinodeStyle = fg(t.Secondary)
+
+	robotSingleStyle = fg(t.Success)
+	robotStackedStyle = fg(t.Error)
+	midStyle = lipgloss.NewStyle().Background(t.Background)
+
+	robotStyle = fg(t.Accent)
+	wallStyle = fg(t.Secondary)
+	boxStyle = fg(t.Primary)
+
+	heightRenders = renderHeights(false)
+	heightVisitedRenders = renderHeights(true)
+
+	robotSingleRender = robotSingleStyle.Render(string(robotChar))
+	robotStackedRender = robotStackedStyle.Render(string(robotChar))
+	midRender = midStyle.Render(".")
+
+	day4Renderer = NewBoardRenderer(func(r rune) string { return "." }).WithStyleFunc(func(r rune) (lipgloss.Style, bool) {
+		return letterStyle, true
+	})
+	day4FoundRenderer = NewRuneRenderer().WithStyleFunc(func(r rune) (lipgloss.Style, bool) {
+		return foundStyle, true
+	})
+
+	day6Styles := map[rune]lipgloss.Style{'X': pathStyle, '#': obstacleStyle}
+	for _, dir := range cardinalDirections {
+		day6Styles[dir.Char()] = guardStyle
+	}
+	day6Renderer = NewRuneRenderer().WithStyles(day6Styles)
+
+	day8Renderer = NewRuneRenderer().WithStyleFunc(func(r rune) (lipgloss.Style, bool) {
+		return antennaStyle, r != '.'
+	})
+	day8AntinodeRenderer = NewBoardRenderer(func(r rune) string {
+		if r == '.' {
+			return "#"
+		}
+		return string(r)
+	}).WithStyleFunc(func(r rune) (lipgloss.Style, bool) {
+		if r == '.' {
+			return antinodeStyle, true
+		}
+		return antennaWithAntinodeStyle, true
+	})
+
+	day12Renderer = NewBoardRenderer(func(plot day12Plot) string { return string(plot.plotType) }).WithStyleFunc(func(plot day12Plot) (lipgloss.Style, bool) {
+		return fg(plotColor(plot)), true
+	})
+
+	day15Renderer = NewRuneRenderer().WithStyles(map[rune]lipgloss.Style{
+		wall:  wallStyle,
+		box:   boxStyle,
+		robot: robotStyle,
+	})
+}
+
+// renderHeights renders the digits 0-9 along the theme's ramp
+func renderHeights(visited bool) []string {
+	renders := make([]string, 10)
+	for height := range renders {
+		renders[height] = fg(theme.RampColor(height, visited)).Render(strconv.Itoa(height))
+	}
+	return renders
+}
+
+// fg creates a style with a foreground color
+func fg(c lipgloss.TerminalColor) lipgloss.Style {
+	return lipgloss.NewStyle().Foreground(c)
+}
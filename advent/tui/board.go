@@ -0,0 +1,240 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sirgwain/advent-of-code-2024/advent/color"
+)
+
+// RenderMode controls how a Board is drawn in the viewport
+type RenderMode int
+
+const (
+	// RenderAuto renders at full size if the board fits in the window, otherwise it picks a compact renderer
+	RenderAuto RenderMode = iota
+	// RenderFull renders one character per board cell
+	RenderFull
+	// RenderHalfBlock packs two rows into each character with ▀ and ▄
+	RenderHalfBlock
+	// RenderBraille packs 2x4 cells into each character with braille dots
+	RenderBraille
+)
+
+// ParseRenderMode converts a flag value into a RenderMode
+func ParseRenderMode(s string) (RenderMode, error) {
+	switch s {
+	case "", "auto":
+		return RenderAuto, nil
+	case "full":
+		return RenderFull, nil
+	case "half", "halfblock":
+		return RenderHalfBlock, nil
+	case "braille":
+		return RenderBraille, nil
+	}
+	return RenderAuto, fmt.Errorf("unknown render mode %s, expected one of auto, full, half, braille", s)
+}
+
+// Cell is a single styled character on a Board
+// Cells with no foreground or background color are treated as empty by the compact renderers
+type Cell struct {
+	Char rune
+	Fg   lipgloss.TerminalColor
+	Bg   lipgloss.TerminalColor
+}
+
+// Board is a 2D grid of cells that the Model can render at full size or compacted to fit the window
+type Board interface {
+	Size() (width, height int)
+	Cell(x, y int) Cell
+}
+
+// Cells is a Board backed by a slice of cells
+type Cells struct {
+	width  int
+	height int
+	cells  []Cell
+}
+
+func NewCells(width, height int) *Cells {
+	return &Cells{width: width, height: height, cells: make([]Cell, width*height)}
+}
+
+func (c *Cells) Size() (width, height int) {
+	return c.width, c.height
+}
+
+func (c *Cells) Cell(x, y int) Cell {
+	return c.cells[y*c.width+x]
+}
+
+func (c *Cells) Set(x, y int, cell Cell) {
+	c.cells[y*c.width+x] = cell
+}
+
+// snapshot copies a board so the ui can render it while a solver keeps updating the original
+func snapshot(b Board) *Cells {
+	width, height := b.Size()
+	cells := NewCells(width, height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			cells.cells[y*width+x] = b.Cell(x, y)
+		}
+	}
+	return cells
+}
+
+func hasColor(c lipgloss.TerminalColor) bool {
+	if c == nil {
+		return false
+	}
+	_, noColor := c.(lipgloss.NoColor)
+	return !noColor
+}
+
+// filled returns true if this cell has a color and should be drawn by compact renderers
+func (c Cell) filled() bool {
+	return hasColor(c.Fg) || hasColor(c.Bg)
+}
+
+// color returns the color a compact renderer should use for this cell
+func (c Cell) color() lipgloss.TerminalColor {
+	if hasColor(c.Fg) {
+		return c.Fg
+	}
+	return c.Bg
+}
+
+// cellRenders caches rendered cells so we don't create a new style for every cell on every frame
+type cellRenders map[Cell]string
+
+func (r cellRenders) render(cell Cell) string {
+	if s, ok := r[cell]; ok {
+		return s
+	}
+
+	char := cell.Char
+	if char == 0 {
+		char = ' '
+	}
+	s := string(char)
+	if cell.filled() {
+		style := lipgloss.NewStyle()
+		if hasColor(cell.Fg) {
+			style = style.Foreground(cell.Fg)
+		}
+		if hasColor(cell.Bg) {
+			style = style.Background(cell.Bg)
+		}
+		s = style.Render(s)
+	}
+	r[cell] = s
+	return s
+}
+
+// renderedSize returns the width and height of a board drawn with a render mode
+func renderedSize(mode RenderMode, width, height int) (int, int) {
+	switch mode {
+	case RenderHalfBlock:
+		return width, (height + 1) / 2
+	case RenderBraille:
+		return (width + 1) / 2, (height + 3) / 4
+	}
+	return width, height
+}
+
+// renderedPosition converts a board position to its column and line when drawn with a render mode
+func renderedPosition(mode RenderMode, x, y int) (int, int) {
+	switch mode {
+	case RenderHalfBlock:
+		return x, y / 2
+	case RenderBraille:
+		return x / 2, y / 4
+	}
+	return x, y
+}
+
+// renderFull draws one character per cell
+func (r cellRenders) renderFull(b Board) string {
+	width, height := b.Size()
+	var sb strings.Builder
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			sb.WriteString(r.render(b.Cell(x, y)))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// renderHalfBlock draws two rows per line, the top cell as the foreground of a ▀ and
+// the bottom as the background
+func (r cellRenders) renderHalfBlock(b Board) string {
+	width, height := b.Size()
+	var sb strings.Builder
+	for y := 0; y < height; y += 2 {
+		for x := 0; x < width; x++ {
+			top := b.Cell(x, y)
+			var bottom Cell
+			if y+1 < height {
+				bottom = b.Cell(x, y+1)
+			}
+
+			switch {
+			case top.filled() && bottom.filled():
+				sb.WriteString(r.render(Cell{Char: '▀', Fg: top.color(), Bg: bottom.color()}))
+			case top.filled():
+				sb.WriteString(r.render(Cell{Char: '▀', Fg: top.color()}))
+			case bottom.filled():
+				sb.WriteString(r.render(Cell{Char: '▄', Fg: bottom.color()}))
+			default:
+				sb.WriteRune(' ')
+			}
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// braille dots for each cell in a 2x4 block
+// 1 4
+// 2 5
+// 3 6
+// 7 8
+var brailleDots = [4][2]rune{
+	{0x01, 0x08},
+	{0x02, 0x10},
+	{0x04, 0x20},
+	{0x40, 0x80},
+}
+
+// renderBraille draws 2x4 cells per character, blending the colors of all filled cells
+func (r cellRenders) renderBraille(b Board) string {
+	width, height := b.Size()
+	var sb strings.Builder
+	colors := make([]lipgloss.TerminalColor, 0, 8)
+	for y := 0; y < height; y += 4 {
+		for x := 0; x < width; x += 2 {
+			var dots rune
+			colors = colors[:0]
+			for dy := 0; dy < 4 && y+dy < height; dy++ {
+				for dx := 0; dx < 2 && x+dx < width; dx++ {
+					cell := b.Cell(x+dx, y+dy)
+					if cell.filled() {
+						dots |= brailleDots[dy][dx]
+						colors = append(colors, cell.color())
+					}
+				}
+			}
+			if dots == 0 {
+				sb.WriteRune(' ')
+				continue
+			}
+			sb.WriteString(r.render(Cell{Char: 0x2800 + dots, Fg: color.Blend(colors...)}))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,152 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Inspector describes what a solver knows about a board position. Its output is shown
+// in a side panel when moving the cursor over the board in inspect mode
+type Inspector func(x, y int) string
+
+// Gate lets the ui pause a solver running in another goroutine so an Inspector can safely
+// read the solver's state. The solver holds the gate while it runs, calling Step between
+// moves and Done when it's finished.
+type Gate struct {
+	mu sync.Mutex
+}
+
+// NewGate creates a gate held by the solver
+func NewGate() *Gate {
+	g := &Gate{}
+	g.mu.Lock()
+	return g
+}
+
+// Step gives the ui a chance to pause the solver. It blocks while the ui is inspecting.
+func (g *Gate) Step() {
+	g.mu.Unlock()
+	g.mu.Lock()
+}
+
+// Done releases the gate for good once the solver is finished
+func (g *Gate) Done() {
+	g.mu.Unlock()
+}
+
+// sent when the ui has acquired the gate and the solver is paused
+type gatePaused struct{}
+
+// pause waits for the solver to reach a Step outside the event loop, so solvers blocked
+// sending messages to the program can still make progress
+func (g *Gate) pause() tea.Cmd {
+	return func() tea.Msg {
+		g.mu.Lock()
+		return gatePaused{}
+	}
+}
+
+func (g *Gate) resume() {
+	g.mu.Unlock()
+}
+
+const inspectPanelWidth = 32
+
+var (
+	inspectPanelStyle = lipgloss.NewStyle().
+				Width(inspectPanelWidth).
+				BorderStyle(lipgloss.RoundedBorder()).
+				Padding(0, 1)
+	cursorStyle = lipgloss.NewStyle().Reverse(true)
+)
+
+// WithInspector enables inspect mode on boards sent with UpdateBoard. If gate is not nil, the solver
+// is paused while inspecting.
+func (m Model) WithInspector(inspector Inspector, gate *Gate) Model {
+	m.inspector = inspector
+	m.gate = gate
+	return m
+}
+
+// toggleInspect turns inspect mode on or off, pausing or resuming the solver
+func (m Model) toggleInspect() (Model, tea.Cmd) {
+	if m.inspector == nil || m.board == nil {
+		return m, nil
+	}
+
+	m.inspecting = !m.inspecting
+	var cmd tea.Cmd
+	if m.inspecting {
+		m.cursorX = min(m.cursorX, m.board.width-1)
+		m.cursorY = min(m.cursorY, m.board.height-1)
+		if m.gate != nil {
+			cmd = m.gate.pause()
+		}
+	} else if m.paused {
+		m.gate.resume()
+		m.paused = false
+	}
+
+	m.setBoardContent()
+	return m, cmd
+}
+
+// moveCursor moves the inspect cursor and scrolls the viewport to keep it visible
+func (m *Model) moveCursor(dx, dy int) {
+	m.cursorX = maxInt(0, minInt(m.board.width-1, m.cursorX+dx))
+	m.cursorY = maxInt(0, minInt(m.board.height-1, m.cursorY+dy))
+
+	if m.cursorY < m.viewport.YOffset {
+		m.viewport.SetYOffset(m.cursorY)
+	} else if m.cursorY >= m.viewport.YOffset+m.viewport.Height {
+		m.viewport.SetYOffset(m.cursorY - m.viewport.Height + 1)
+	}
+	if visible := m.visibleWidth(); m.cursorX < m.xOffset {
+		m.xOffset = m.cursorX
+	} else if m.cursorX >= m.xOffset+visible {
+		m.xOffset = m.cursorX - visible + 1
+	}
+	m.setBoardContent()
+}
+
+// inspectView renders the side panel for the cursor position
+func (m Model) inspectView() string {
+	var details string
+	if m.gate != nil && !m.paused {
+		details = "pausing..."
+	} else {
+		details = m.inspector(m.cursorX, m.cursorY)
+	}
+	return inspectPanelStyle.Render(fmt.Sprintf("%d,%d\n\n%s", m.cursorX, m.cursorY, details))
+}
+
+// renderCursor draws the board at full size with the cell under the cursor highlighted
+func (r cellRenders) renderCursor(b Board, cursorX, cursorY int) string {
+	width, height := b.Size()
+	var sb strings.Builder
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			cell := b.Cell(x, y)
+			if x != cursorX || y != cursorY {
+				sb.WriteString(r.render(cell))
+				continue
+			}
+
+			style := cursorStyle
+			if hasColor(cell.Fg) {
+				style = style.Foreground(cell.Fg)
+			}
+			char := cell.Char
+			if char == 0 {
+				char = ' '
+			}
+			sb.WriteString(style.Render(string(char)))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,229 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// MenuItem is a runnable entry in the launcher, like a day
+type MenuItem struct {
+	Title  string
+	Visual bool     // true if this item has a visualization
+	Inputs []string // input files for this item
+}
+
+// MenuChoice holds what the user picked in the launcher
+type MenuChoice struct {
+	Item     int
+	Part     int
+	Input    string
+	Visual   bool
+	Delay    int
+	Redacted bool
+	Quit     bool
+}
+
+// settings fields on the right side of the launcher
+type menuField int
+
+const (
+	menuFieldPart menuField = iota
+	menuFieldInput
+	menuFieldVisual
+	menuFieldDelay
+	menuFieldRedacted
+	numMenuFields
+)
+
+// how much to change the delay with each key press
+const menuDelayStep = 10
+
+var (
+	menuSelectedStyle = lipgloss.NewStyle().Reverse(true)
+	menuDimStyle      = lipgloss.NewStyle().Faint(true)
+	menuColumnStyle   = lipgloss.NewStyle().MarginRight(4)
+)
+
+// Menu is a launcher that lists items on the left and their run settings on the right
+type Menu struct {
+	title  string
+	items  []MenuItem
+	choice MenuChoice
+
+	// focus is on the settings instead of the list of items
+	settingsFocus bool
+	field         menuField
+}
+
+// NewMenu creates a launcher, starting with a previous choice selected
+func NewMenu(title string, items []MenuItem, choice MenuChoice) Menu {
+	m := Menu{title: title, items: items, choice: choice}
+	m.choice.Quit = false
+	m.choice.Item = maxInt(0, minInt(len(items)-1, choice.Item))
+	if m.choice.Part != 2 {
+		m.choice.Part = 1
+	}
+	m.selectItem(m.choice.Item)
+	return m
+}
+
+// RunMenu shows the launcher until the user picks an item to run or quits
+func RunMenu(menu Menu) (MenuChoice, error) {
+	model, err := tea.NewProgram(menu, tea.WithAltScreen()).Run()
+	if err != nil {
+		return MenuChoice{}, fmt.Errorf("could not start menu: %w", err)
+	}
+	return model.(Menu).choice, nil
+}
+
+// selectItem moves to a new item, keeping the input file if the item has it
+func (m *Menu) selectItem(item int) {
+	m.choice.Item = item
+	inputs := m.items[item].Inputs
+	if len(inputs) == 0 {
+		m.choice.Input = ""
+	} else if m.inputIndex() == -1 {
+		m.choice.Input = inputs[0]
+	}
+}
+
+func (m Menu) inputIndex() int {
+	for i, input := range m.items[m.choice.Item].Inputs {
+		if input == m.choice.Input {
+			return i
+		}
+	}
+	return -1
+}
+
+// changeField changes the value of the selected setting, dir is -1 for left and 1 for right
+func (m *Menu) changeField(dir int) {
+	switch m.field {
+	case menuFieldPart:
+		m.choice.Part = 3 - m.choice.Part
+	case menuFieldInput:
+		inputs := m.items[m.choice.Item].Inputs
+		if len(inputs) > 0 {
+			m.choice.Input = inputs[(m.inputIndex()+dir+len(inputs))%len(inputs)]
+		}
+	case menuFieldVisual:
+		m.choice.Visual = !m.choice.Visual
+	case menuFieldDelay:
+		m.choice.Delay = maxInt(0, m.choice.Delay+dir*menuDelayStep)
+	case menuFieldRedacted:
+		m.choice.Redacted = !m.choice.Redacted
+	}
+}
+
+func (m Menu) Init() tea.Cmd {
+	return nil
+}
+
+func (m Menu) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "q", "esc":
+			m.choice.Quit = true
+			return m, tea.Quit
+		case "enter":
+			if m.choice.Input != "" {
+				return m, tea.Quit
+			}
+		case "tab", "shift+tab":
+			m.settingsFocus = !m.settingsFocus
+		case "up", "k":
+			if m.settingsFocus {
+				m.field = (m.field + numMenuFields - 1) % numMenuFields
+			} else {
+				m.selectItem(maxInt(0, m.choice.Item-1))
+			}
+		case "down", "j":
+			if m.settingsFocus {
+				m.field = (m.field + 1) % numMenuFields
+			} else {
+				m.selectItem(minInt(len(m.items)-1, m.choice.Item+1))
+			}
+		case "left", "h":
+			if m.settingsFocus {
+				m.changeField(-1)
+			}
+		case "right", "l", " ":
+			if m.settingsFocus {
+				m.changeField(1)
+			} else {
+				m.settingsFocus = true
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m Menu) itemsView() string {
+	var sb strings.Builder
+	for i, item := range m.items {
+		visual := " "
+		if item.Visual {
+			visual = "✦"
+		}
+		line := fmt.Sprintf(" %2d %s %-24s", i+1, visual, item.Title)
+		if i == m.choice.Item {
+			if m.settingsFocus {
+				line = menuDimStyle.Inherit(menuSelectedStyle).Render(line)
+			} else {
+				line = menuSelectedStyle.Render(line)
+			}
+		}
+		sb.WriteString(line + "\n")
+	}
+	return sb.String()
+}
+
+func (m Menu) settingsView() string {
+	checkbox := func(checked bool) string {
+		if checked {
+			return "[x]"
+		}
+		return "[ ]"
+	}
+
+	input := m.choice.Input
+	if input == "" {
+		input = "no inputs found"
+	}
+	visual := checkbox(m.choice.Visual)
+	if !m.items[m.choice.Item].Visual {
+		visual = menuDimStyle.Render("not available")
+	}
+
+	fields := []struct {
+		name  string
+		value string
+	}{
+		{"Part", fmt.Sprintf("< %d >", m.choice.Part)},
+		{"Input", fmt.Sprintf("< %s >", input)},
+		{"Visualize", visual},
+		{"Delay", fmt.Sprintf("< %dms >", m.choice.Delay)},
+		{"Redacted", checkbox(m.choice.Redacted)},
+	}
+
+	var sb strings.Builder
+	for i, field := range fields {
+		name := fmt.Sprintf("%-10s", field.name)
+		if m.settingsFocus && menuField(i) == m.field {
+			name = menuSelectedStyle.Render(name)
+		}
+		sb.WriteString(fmt.Sprintf("%s %s\n", name, field.value))
+	}
+	return sb.String()
+}
+
+func (m Menu) View() string {
+	title := titleStyle.Render(m.title)
+	body := lipgloss.JoinHorizontal(lipgloss.Top, menuColumnStyle.Render(m.itemsView()), m.settingsView())
+	help := menuDimStyle.Render("↑/↓ select · tab settings · ←/→ change · enter run · q quit · ✦ has visualization")
+	return mainStyle.Render(fmt.Sprintf("%s\n%s\n%s", title, body, help))
+}
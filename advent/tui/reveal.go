@@ -0,0 +1,17 @@
+package tui
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// revealMsg holds back a message until the user presses r
+type revealMsg struct {
+	msg tea.Msg
+}
+
+// Reveal wraps a message, usually a board or viewport update with the unredacted solution,
+// so it is only applied when the user presses r at the end of a run
+func Reveal(msg tea.Msg) tea.Msg {
+	return revealMsg{msg: msg}
+}
+
+// revealHint is shown in the footer while a reveal is waiting
+const revealHint = " r to reveal "
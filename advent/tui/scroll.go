@@ -0,0 +1,88 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// how many columns to scroll horizontally for each key press or wheel tick
+const horizontalStep = 4
+
+// Focuser is implemented by boards with a point of interest, like a robot or a guard,
+// that the viewport can keep centered in follow mode
+type Focuser interface {
+	Focus() (x, y int)
+}
+
+// visibleWidth is the number of content columns that fit in the window
+func (m Model) visibleWidth() int {
+	width := m.windowWidth - mainStyle.GetHorizontalMargins() - viewportStyle.GetHorizontalMargins()
+	if m.inspecting {
+		width -= lipgloss.Width(m.inspectView())
+	}
+	return maxInt(1, width)
+}
+
+// setContent replaces the viewport content, keeping the full lines so we can scroll horizontally
+func (m *Model) setContent(content string) {
+	m.viewportLines = strings.Split(content, "\n")
+	m.cropContent()
+}
+
+// cropContent sets the viewport to the visible columns of the content
+func (m *Model) cropContent() {
+	contentWidth := 0
+	for _, line := range m.viewportLines {
+		contentWidth = maxInt(contentWidth, lipgloss.Width(line))
+	}
+
+	visible := m.visibleWidth()
+	m.xOffset = maxInt(0, minInt(m.xOffset, contentWidth-visible))
+	if contentWidth <= visible {
+		m.viewport.SetContent(strings.Join(m.viewportLines, "\n"))
+		return
+	}
+
+	lines := make([]string, len(m.viewportLines))
+	for i, line := range m.viewportLines {
+		lines[i] = cutLine(line, m.xOffset, visible)
+	}
+	m.viewport.SetContent(strings.Join(lines, "\n"))
+}
+
+// scrollHorizontal moves the visible columns left (negative) or right
+func (m *Model) scrollHorizontal(columns int) {
+	m.xOffset = maxInt(0, m.xOffset+columns)
+	m.cropContent()
+}
+
+// followFocus centers the viewport on the board's point of interest
+func (m *Model) followFocus(mode RenderMode) {
+	x, y := renderedPosition(mode, m.focusX, m.focusY)
+	m.xOffset = maxInt(0, x-m.visibleWidth()/2)
+	m.viewport.SetYOffset(y - m.viewport.Height/2)
+}
+
+// cutLine returns the columns of a line from left to left+width. All ansi escape sequences
+// are kept so styles carry over from the columns that were cut
+func cutLine(line string, left, width int) string {
+	var sb strings.Builder
+	var state byte
+	col := 0
+	for len(line) > 0 {
+		seq, seqWidth, n, newState := ansi.DecodeSequence(line, state, nil)
+		state = newState
+		line = line[n:]
+		if seqWidth == 0 {
+			sb.WriteString(seq)
+			continue
+		}
+		if col >= left && col+seqWidth <= left+width {
+			sb.WriteString(seq)
+		}
+		col += seqWidth
+	}
+	return sb.String()
+}
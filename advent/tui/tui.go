@@ -30,6 +30,31 @@ type Model struct {
 	minWidth      int
 	windowWidth   int
 	windowHeight  int
+
+	// boards are rendered by the model so they can be compacted to fit the window
+	renderMode  RenderMode
+	board       *Cells
+	boardFooter string
+	cellRenders cellRenders
+
+	// inspect mode moves a cursor over the board and shows what the solver knows about it
+	inspector  Inspector
+	gate       *Gate
+	inspecting bool
+	paused     bool
+	cursorX    int
+	cursorY    int
+
+	// horizontal scrolling, following the board's focus and fitting the board to the window
+	xOffset  int
+	follow   bool
+	hasFocus bool
+	focusX   int
+	focusY   int
+	fit      bool
+
+	// a message with the unredacted solution, applied when the user presses r
+	reveal tea.Msg
 }
 
 // custom messages
@@ -43,10 +68,17 @@ type (
 		lineNum int
 		line    string
 	}
+	updateBoard struct {
+		board    *Cells
+		footer   string
+		hasFocus bool
+		focusX   int
+		focusY   int
+	}
 )
 
 func NewModel(title string) Model {
-	return Model{title: title}
+	return Model{title: title, cellRenders: cellRenders{}, fit: true}
 }
 
 func (m Model) WithViewport(lines []string) Model {
@@ -59,6 +91,12 @@ func (m Model) WithMinWidth(minWidth int) Model {
 	return m
 }
 
+func (m Model) WithRenderMode(mode RenderMode) Model {
+	m.renderMode = mode
+	m.fit = mode == RenderAuto
+	return m
+}
+
 func NewViewportProgram(initialModel Model) *tea.Program {
 	return tea.NewProgram(
 		initialModel,
@@ -75,6 +113,24 @@ func UpdateViewportLine(lineNum int, line string) tea.Msg {
 	return updateViewportLine{lineNum: lineNum, line: line}
 }
 
+// UpdateBoard replaces the viewport content with a board, rendered with the model's RenderMode,
+// followed by a footer. Boards are copied before sending so solvers can keep updating them.
+// *Cells are sent as is and must not be modified after sending.
+func UpdateBoard(board Board, footer string) tea.Msg {
+	msg := updateBoard{footer: footer}
+	if focuser, ok := board.(Focuser); ok {
+		msg.hasFocus = true
+		msg.focusX, msg.focusY = focuser.Focus()
+	}
+
+	cells, ok := board.(*Cells)
+	if !ok {
+		cells = snapshot(board)
+	}
+	msg.board = cells
+	return msg
+}
+
 func minInt(nums ...int) int {
 	result := math.MaxInt
 	for _, value := range nums {
@@ -104,8 +160,65 @@ func (m Model) headerView() string {
 }
 
 func (m Model) footerView() string {
-	line := strings.Repeat("─", max(0, m.viewport.Width))
-	return lipgloss.JoinHorizontal(lipgloss.Center, line)
+	var hint string
+	if m.reveal != nil {
+		hint = revealHint
+	}
+	line := strings.Repeat("─", max(0, m.viewport.Width-lipgloss.Width(hint)))
+	return lipgloss.JoinHorizontal(lipgloss.Center, line, hint)
+}
+
+// boardRenderMode picks the render mode for the current board. In fit mode the board
+// is compacted if it doesn't fit in the window
+func (m Model) boardRenderMode() RenderMode {
+	if !m.fit {
+		if m.renderMode == RenderAuto {
+			return RenderFull
+		}
+		return m.renderMode
+	}
+
+	width, height := m.board.Size()
+	availableWidth := m.windowWidth - mainStyle.GetHorizontalMargins() - viewportStyle.GetHorizontalMargins()
+	availableHeight := m.viewport.Height - lipgloss.Height(m.boardFooter)
+	for _, mode := range []RenderMode{RenderFull, RenderHalfBlock} {
+		w, h := renderedSize(mode, width, height)
+		if w <= availableWidth && h <= availableHeight {
+			return mode
+		}
+	}
+	return RenderBraille
+}
+
+// setBoardContent renders the board and footer into the viewport
+func (m *Model) setBoardContent() {
+	mode := m.boardRenderMode()
+
+	var content string
+	switch {
+	case m.inspecting:
+		// the cursor is on a single cell, so always draw inspected boards at full size
+		mode = RenderFull
+		content = m.cellRenders.renderCursor(m.board, m.cursorX, m.cursorY)
+	case mode == RenderHalfBlock:
+		content = m.cellRenders.renderHalfBlock(m.board)
+	case mode == RenderBraille:
+		content = m.cellRenders.renderBraille(m.board)
+	default:
+		content = m.cellRenders.renderFull(m.board)
+	}
+
+	width, _ := renderedSize(mode, m.board.width, m.board.height)
+	windowWidth := m.windowWidth
+	if m.inspecting {
+		// leave room for the inspect panel
+		windowWidth -= lipgloss.Width(m.inspectView())
+	}
+	m.viewport.Width = maxInt(minInt(m.minWidth, windowWidth), minInt(windowWidth, width)) + viewportStyle.GetHorizontalMargins()
+	if m.follow && m.hasFocus && !m.inspecting {
+		m.followFocus(mode)
+	}
+	m.setContent(content + "\n" + m.boardFooter)
 }
 
 // default init, does nothing
@@ -122,10 +235,80 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		if k := msg.String(); k == "ctrl+c" || k == "q" || k == "esc" {
+		if m.inspecting {
+			switch msg.String() {
+			case "i", "esc":
+				return m.toggleInspect()
+			case "up", "k":
+				m.moveCursor(0, -1)
+				return m, nil
+			case "down", "j":
+				m.moveCursor(0, 1)
+				return m, nil
+			case "left", "h":
+				m.moveCursor(-1, 0)
+				return m, nil
+			case "right", "l":
+				m.moveCursor(1, 0)
+				return m, nil
+			}
+		}
+		switch msg.String() {
+		case "ctrl+c", "q", "esc":
 			return m, tea.Quit
+		case "i":
+			return m.toggleInspect()
+		case "shift+left":
+			m.scrollHorizontal(-horizontalStep)
+			return m, nil
+		case "shift+right":
+			m.scrollHorizontal(horizontalStep)
+			return m, nil
+		case "f":
+			m.follow = !m.follow
+			if m.board != nil {
+				m.setBoardContent()
+			}
+			return m, nil
+		case "z":
+			m.fit = !m.fit
+			if m.board != nil {
+				m.setBoardContent()
+			}
+			return m, nil
+		case "r":
+			if m.reveal != nil {
+				reveal := m.reveal
+				m.reveal = nil
+				return m.Update(reveal)
+			}
 		}
 
+	case tea.MouseMsg:
+		// the viewport only scrolls vertically, so handle horizontal wheel events ourselves
+		switch {
+		case msg.Button == tea.MouseButtonWheelLeft || (msg.Shift && msg.Button == tea.MouseButtonWheelUp):
+			m.scrollHorizontal(-horizontalStep)
+			return m, nil
+		case msg.Button == tea.MouseButtonWheelRight || (msg.Shift && msg.Button == tea.MouseButtonWheelDown):
+			m.scrollHorizontal(horizontalStep)
+			return m, nil
+		}
+
+	case revealMsg:
+		m.reveal = msg.msg
+		return m, nil
+
+	case gatePaused:
+		if !m.inspecting {
+			// we stopped inspecting before the solver paused, let it continue
+			m.gate.resume()
+			return m, nil
+		}
+		m.paused = true
+		m.setBoardContent()
+		return m, nil
+
 	case tea.WindowSizeMsg:
 		headerHeight := lipgloss.Height(m.headerView())
 		footerHeight := lipgloss.Height(m.footerView())
@@ -145,7 +328,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.viewport.YPosition = headerHeight
 			m.ready = true
 			if m.viewportLines != nil {
-				m.viewport.SetContent(strings.Join(m.viewportLines, "\n"))
+				m.cropContent()
+			}
+			if m.board != nil {
+				m.setBoardContent()
 			}
 
 			// This is only necessary for high performance rendering, which in
@@ -154,10 +340,19 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Render the viewport one line below the header.
 			m.viewport.YPosition = headerHeight + 1
 		} else {
+			m.windowWidth, m.windowHeight = msg.Width, msg.Height
 			m.viewport.Width = msg.Width
 			m.viewport.Height = msg.Height - verticalMarginHeight
+			if m.board != nil {
+				// the window size may change how the board is compacted
+				m.setBoardContent()
+			} else {
+				m.cropContent()
+			}
 		}
 	case updateViewport:
+		m.board = nil
+		m.hasFocus = false
 		m.viewportLines = strings.Split(msg.content, "\n")
 		if m.ready {
 			if msg.width != 0 {
@@ -166,12 +361,19 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if msg.height != 0 {
 				m.viewport.Height = maxInt(m.minWidth, minInt(m.windowHeight, msg.height))
 			}
-			m.viewport.SetContent(msg.content)
+			m.cropContent()
+		}
+	case updateBoard:
+		m.board = msg.board
+		m.boardFooter = msg.footer
+		m.hasFocus, m.focusX, m.focusY = msg.hasFocus, msg.focusX, msg.focusY
+		if m.ready {
+			m.setBoardContent()
 		}
 	case updateViewportLine:
 		if m.ready && msg.lineNum >= 0 && msg.lineNum < len(m.viewportLines) {
 			m.viewportLines[msg.lineNum] = msg.line
-			m.viewport.SetContent(strings.Join(m.viewportLines, "\n"))
+			m.cropContent()
 		}
 	}
 
@@ -184,5 +386,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // The main view renders the header, viewport and footer
 func (m Model) View() string {
-	return mainStyle.Render(fmt.Sprintf("%s\n%s\n%s", m.headerView(), viewportStyle.Render(m.viewport.View()), m.footerView()))
+	viewport := viewportStyle.Render(m.viewport.View())
+	if m.inspecting {
+		viewport = lipgloss.JoinHorizontal(lipgloss.Top, viewport, m.inspectView())
+	}
+	return mainStyle.Render(fmt.Sprintf("%s\n%s\n%s", m.headerView(), viewport, m.footerView()))
 }
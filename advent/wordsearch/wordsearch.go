@@ -0,0 +1,244 @@
+// Package wordsearch finds words and 2D patterns on a board of runes, in every direction
+// they can be read and every way they can be turned or flipped.
+package wordsearch
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+	"strings"
+
+	"github.com/sirgwain/advent-of-code-2024/advent/geom"
+)
+
+// Wildcard matches any rune in a pattern
+const Wildcard = '.'
+
+// Pattern is a word or a 2D shape to find. Patterns are rows of runes, and Wildcards match anything.
+type Pattern struct {
+	Name string
+	rows [][]rune
+}
+
+// Word is a pattern for a single word, which can be read in any of the 8 directions
+func Word(word string) (Pattern, error) {
+	return newPattern(word, [][]rune{[]rune(word)})
+}
+
+// MustWord is Word for words known to be valid
+func MustWord(word string) Pattern {
+	p, err := Word(word)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// ParsePattern parses a pattern with rows separated by /, like M.M/.A./S.S. A pattern without a /
+// is a word.
+func ParsePattern(s string) (Pattern, error) {
+	var rows [][]rune
+	for _, row := range strings.Split(s, "/") {
+		rows = append(rows, []rune(row))
+	}
+	return newPattern(s, rows)
+}
+
+// MustParsePattern is ParsePattern for patterns known to be valid
+func MustParsePattern(s string) Pattern {
+	p, err := ParsePattern(s)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// newPattern checks that a pattern's rows are all the same length and it has something to match
+func newPattern(name string, rows [][]rune) (Pattern, error) {
+	for _, row := range rows {
+		if len(row) == 0 {
+			return Pattern{}, fmt.Errorf("pattern %q has an empty row", name)
+		}
+		if len(row) != len(rows[0]) {
+			return Pattern{}, fmt.Errorf("pattern %q rows are different lengths", name)
+		}
+	}
+	if !slices.ContainsFunc(rows, func(row []rune) bool { return slices.ContainsFunc(row, func(r rune) bool { return r != Wildcard }) }) {
+		return Pattern{}, fmt.Errorf("pattern %q is only wildcards", name)
+	}
+	return Pattern{Name: name, rows: rows}, nil
+}
+
+// Orientation is how a pattern was turned to match. Direction is the way the pattern's rows
+// read on the board, and Mirrored is set if the pattern was flipped over, so its rows go down
+// the board counterclockwise from Direction instead of clockwise.
+type Orientation struct {
+	Direction geom.Direction
+	Mirrored  bool
+}
+
+func (o Orientation) String() string {
+	if o.Mirrored {
+		return o.Direction.String() + " mirrored"
+	}
+	return o.Direction.String()
+}
+
+// Cell is one rune of a pattern, placed on the board
+type Cell struct {
+	geom.Point
+	Char rune
+}
+
+// Match is a pattern found on the board
+type Match struct {
+	Pattern     *Pattern
+	Orientation Orientation
+	// Start is where the pattern's first row and column is on the board. For patterns that start with
+	// a wildcard, like .A./M.S, nothing has to match there, so use Cells for the runes that matched.
+	Start geom.Point
+	// Cells are the runes that matched, not including wildcards, in pattern order
+	Cells []Cell
+}
+
+// orientedPattern is a pattern turned one way, as offsets from its first row and column
+type orientedPattern struct {
+	pattern     *Pattern
+	orientation Orientation
+	cells       []Cell
+}
+
+// Searcher finds patterns on boards
+type Searcher struct {
+	patterns []Pattern
+	oriented []orientedPattern
+}
+
+// New creates a Searcher for patterns. Words are found reading in all 8 directions, 2D patterns in
+// all 4 rotations and their reflections. Orientations of a symmetric pattern that cover the same runes
+// are only searched once, so each match is only found once.
+func New(patterns ...Pattern) *Searcher {
+	s := &Searcher{patterns: patterns}
+	for i := range s.patterns {
+		p := &s.patterns[i]
+		seen := map[string]bool{}
+		for _, o := range p.orientations() {
+			cells := p.place(o)
+			if key := shapeKey(cells); !seen[key] {
+				seen[key] = true
+				s.oriented = append(s.oriented, orientedPattern{pattern: p, orientation: o, cells: cells})
+			}
+		}
+	}
+	return s
+}
+
+// orientations are the ways a pattern can be turned. Words can go diagonally, but 2D patterns only
+// turn in quarter turns, or they would be skewed.
+func (p *Pattern) orientations() []Orientation {
+	var orientations []Orientation
+	if len(p.rows) == 1 {
+		for _, dir := range geom.All {
+			orientations = append(orientations, Orientation{Direction: dir})
+		}
+		return orientations
+	}
+	for _, mirrored := range []bool{false, true} {
+		for _, dir := range geom.Cardinal {
+			orientations = append(orientations, Orientation{Direction: dir, Mirrored: mirrored})
+		}
+	}
+	return orientations
+}
+
+// place turns a pattern and returns its runes as offsets from the first row and column
+func (p *Pattern) place(o Orientation) []Cell {
+	// columns go along the direction, rows go down a quarter turn from it
+	across := o.Direction.Offset()
+	down := o.Direction.TurnRight().Offset()
+	if o.Mirrored {
+		down = o.Direction.TurnLeft().Offset()
+	}
+
+	var cells []Cell
+	for row, runes := range p.rows {
+		for col, r := range runes {
+			if r == Wildcard {
+				continue
+			}
+			cells = append(cells, Cell{Point: across.Scale(col).Add(down.Scale(row)), Char: r})
+		}
+	}
+	return cells
+}
+
+// shapeKey identifies the runes a pattern covers, wherever it starts, so symmetric orientations
+// can be skipped
+func shapeKey(cells []Cell) string {
+	minimum := cells[0].Point
+	for _, c := range cells {
+		minimum = geom.Point{X: min(minimum.X, c.X), Y: min(minimum.Y, c.Y)}
+	}
+	keys := make([]string, len(cells))
+	for i, c := range cells {
+		p := c.Sub(minimum)
+		keys[i] = fmt.Sprintf("%d,%d=%c", p.X, p.Y, c.Char)
+	}
+	slices.Sort(keys)
+	return strings.Join(keys, " ")
+}
+
+// All finds every match on a board, sweeping it row by row from the top left. Matches at the same
+// start are found in pattern order, then orientation order.
+func (s *Searcher) All(board [][]rune) iter.Seq[Match] {
+	at := func(p geom.Point) (rune, bool) {
+		if p.Y < 0 || p.Y >= len(board) || p.X < 0 || p.X >= len(board[p.Y]) {
+			return 0, false
+		}
+		return board[p.Y][p.X], true
+	}
+
+	return func(yield func(Match) bool) {
+		for y := range board {
+			for x := range board[y] {
+				start := geom.Point{X: x, Y: y}
+				for _, o := range s.oriented {
+					if cells, ok := o.match(start, at); ok {
+						if !yield(Match{Pattern: o.pattern, Orientation: o.orientation, Start: start, Cells: cells}) {
+							return
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// Find returns every match on a board
+func (s *Searcher) Find(board [][]rune) []Match {
+	return slices.Collect(s.All(board))
+}
+
+// Count counts the matches on a board
+func (s *Searcher) Count(board [][]rune) int {
+	count := 0
+	for range s.All(board) {
+		count++
+	}
+	return count
+}
+
+// match checks for the pattern at start and returns the cells it covers on the board
+func (o orientedPattern) match(start geom.Point, at func(p geom.Point) (rune, bool)) ([]Cell, bool) {
+	for _, c := range o.cells {
+		if r, ok := at(start.Add(c.Point)); !ok || r != c.Char {
+			return nil, false
+		}
+	}
+
+	cells := make([]Cell, len(o.cells))
+	for i, c := range o.cells {
+		cells[i] = Cell{Point: start.Add(c.Point), Char: c.Char}
+	}
+	return cells, true
+}
@@ -0,0 +1,106 @@
+package wordsearch
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sirgwain/advent-of-code-2024/advent/geom"
+)
+
+const example = `MMMSXXMASM
+MSAMXMSMSA
+AMXSXMAAMM
+MSAMASMSMX
+XMASAMXAMM
+XXAMMXXAMA
+SMSMSASXSS
+SAXAMASAAA
+MAMMMXMMMM
+MXMXAXMASX`
+
+func board(s string) [][]rune {
+	var rows [][]rune
+	for _, line := range strings.Split(s, "\n") {
+		rows = append(rows, []rune(line))
+	}
+	return rows
+}
+
+func TestCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		board    string
+		patterns []Pattern
+		want     int
+	}{
+		{"xmas", example, []Pattern{MustWord("XMAS")}, 18},
+		{"x-mas", example, []Pattern{MustParsePattern("M.M/.A./S.S")}, 9},
+		{"palindromes are found once", "ABA", []Pattern{MustWord("ABA")}, 1},
+		{"symmetric patterns are found once", "A.A\n.B.\nA.A", []Pattern{MustParsePattern("A.A/.B./A.A")}, 1},
+		{"several patterns", "XMAS\nSAMX", []Pattern{MustWord("XMAS"), MustWord("AM")}, 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := New(tt.patterns...).Count(board(tt.board)); got != tt.want {
+				t.Errorf("Count() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFind(t *testing.T) {
+	matches := New(MustWord("XMAS")).Find(board("..S\n.A.\nM..\nX.."))
+	if len(matches) != 0 {
+		t.Fatalf("Find() found %d matches, want 0", len(matches))
+	}
+
+	matches = New(MustWord("XMAS")).Find(board("...S\n..A.\n.M..\nX..."))
+	if len(matches) != 1 {
+		t.Fatalf("Find() found %d matches, want 1", len(matches))
+	}
+	match := matches[0]
+	if match.Start != (geom.Point{X: 0, Y: 3}) || match.Orientation.Direction != geom.UpRight {
+		t.Errorf("Find() = %v %v, want (0,3) up-right", match.Start, match.Orientation)
+	}
+	if last := match.Cells[3]; last.Point != (geom.Point{X: 3, Y: 0}) || last.Char != 'S' {
+		t.Errorf("Find() last cell = %v %c, want (3,0) S", last.Point, last.Char)
+	}
+
+	// a pattern starting with a wildcard starts where the wildcard is, and its cells skip it
+	matches = New(MustParsePattern(".A./M.S")).Find(board("xAx\nMyS"))
+	if len(matches) != 1 {
+		t.Fatalf("Find() found %d matches, want 1", len(matches))
+	}
+	match = matches[0]
+	if match.Start != (geom.Point{X: 0, Y: 0}) || len(match.Cells) != 3 {
+		t.Errorf("Find() = %v with %d cells, want (0,0) with 3 cells", match.Start, len(match.Cells))
+	}
+	if first := match.Cells[0]; first.Point != (geom.Point{X: 1, Y: 0}) || first.Char != 'A' {
+		t.Errorf("Find() first cell = %v %c, want (1,0) A", first.Point, first.Char)
+	}
+}
+
+func TestParsePattern(t *testing.T) {
+	for _, s := range []string{"M.M/.A", "M//S", "./."} {
+		if _, err := ParsePattern(s); err == nil {
+			t.Errorf("ParsePattern(%q) should fail", s)
+		}
+	}
+}
+
+func TestWord(t *testing.T) {
+	for _, s := range []string{"", ".", "..."} {
+		if _, err := Word(s); err == nil {
+			t.Errorf("Word(%q) should fail", s)
+		}
+	}
+
+	// a word with a wildcard is still a single row, even with a / in it
+	p, err := Word("X.A/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := New(p).Count(board("XMA/")); got != 1 {
+		t.Errorf("Count() = %d, want 1", got)
+	}
+}
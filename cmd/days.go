@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/sirgwain/advent-of-code-2024/advent"
+)
+
+type dayRunner interface {
+	Run(part int, filename string, opts ...advent.Option) error
+}
+
+type dayVisualizer interface {
+	RunVisual(part int, filename string, opts ...advent.Option) error
+}
+
+type dayValidator interface {
+	Validate(filename string) error
+}
+
+// day is a registered day with the puzzle's title
+type day struct {
+	number int
+	title  string
+	runner func() dayRunner
+}
+
+// days are all the days with solutions, in order
+var days = []day{
+	{1, "Historian Hysteria", func() dayRunner { return &advent.Day1{} }},
+	{2, "Red-Nosed Reports", func() dayRunner { return &advent.Day2{} }},
+	{3, "Mull It Over", func() dayRunner { return &advent.Day3{} }},
+	{4, "Ceres Search", func() dayRunner { return &advent.Day4{} }},
+	{5, "Print Queue", func() dayRunner { return &advent.Day5{} }},
+	{6, "Guard Gallivant", func() dayRunner { return &advent.Day6{} }},
+	{7, "Bridge Repair", func() dayRunner { return &advent.Day7{} }},
+	{8, "Resonant Collinearity", func() dayRunner { return &advent.Day8{} }},
+	{9, "Disk Fragmenter", func() dayRunner { return &advent.Day9{} }},
+	{10, "Hoof It", func() dayRunner { return &advent.Day10{} }},
+	{11, "Plutonian Pebbles", func() dayRunner { return &advent.Day11{} }},
+	{12, "Garden Groups", func() dayRunner { return &advent.Day12{} }},
+	{13, "Claw Contraption", func() dayRunner { return &advent.Day13{} }},
+	{14, "Restroom Redoubt", func() dayRunner { return &advent.Day14{} }},
+	{15, "Warehouse Woes", func() dayRunner { return &advent.Day15{} }},
+}
+
+func findDay(number int) (day, error) {
+	for _, d := range days {
+		if d.number == number {
+			return d, nil
+		}
+	}
+	return day{}, fmt.Errorf("day %d not found", number)
+}
+
+// hasVisual returns true if this day has a visualization
+func (d day) hasVisual() bool {
+	_, ok := d.runner().(dayVisualizer)
+	return ok
+}
+
+// inputs returns the files in dir for this day, like day10.txt or day10-example.txt
+func (d day) inputs(dir string) []string {
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	if err != nil {
+		return nil
+	}
+	dayFile := regexp.MustCompile(fmt.Sprintf(`^day%d(\D|$)`, d.number))
+	var inputs []string
+	for _, file := range files {
+		if info, err := os.Stat(file); err != nil || info.IsDir() {
+			continue
+		}
+		if dayFile.MatchString(filepath.Base(file)) {
+			inputs = append(inputs, file)
+		}
+	}
+	return inputs
+}
+
+// run runs a part of this day, with the visualizer if requested and available
+func (d day) run(part int, input string, visualization bool, opts ...advent.Option) error {
+	runner := d.runner()
+	if v, ok := runner.(dayVisualizer); ok && visualization {
+		return v.RunVisual(part, input, opts...)
+	}
+
+	start := time.Now()
+	defer func() { fmt.Printf("\nTime taken %v\n", time.Since(start)) }()
+
+	return runner.Run(part, input, opts...)
+}
+
+// validate checks an input file against this day's format without solving it
+func (d day) validate(input string) error {
+	v, ok := d.runner().(dayValidator)
+	if !ok {
+		return fmt.Errorf("day %d can't validate inputs", d.number)
+	}
+	return v.Validate(input)
+}
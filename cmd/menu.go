@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	"github.com/sirgwain/advent-of-code-2024/advent"
+	"github.com/sirgwain/advent-of-code-2024/advent/tui"
+	"github.com/spf13/cobra"
+)
+
+// runMenu shows the launcher, runs the chosen day and returns to the launcher until the user quits
+func runMenu(inputDir string) error {
+	items := make([]tui.MenuItem, len(days))
+	for i, d := range days {
+		items[i] = tui.MenuItem{
+			Title:  d.title,
+			Visual: d.hasVisual(),
+			Inputs: d.inputs(inputDir),
+		}
+	}
+
+	choice := tui.MenuChoice{Part: 1, Visual: true}
+	for {
+		var err error
+		choice, err = tui.RunMenu(tui.NewMenu("Advent of Code 2024", items, choice))
+		if err != nil {
+			return err
+		}
+		if choice.Quit {
+			return nil
+		}
+
+		d := days[choice.Item]
+		opts := []advent.Option{
+			advent.WithDelay(choice.Delay),
+			advent.WithRedactSolution(choice.Redacted),
+		}
+		if err := d.run(choice.Part, choice.Input, choice.Visual, opts...); err != nil {
+			fmt.Fprintf(os.Stderr, "day %d part %d failed: %v\n", d.number, choice.Part, err)
+		}
+
+		// every run prints its results, visualizations after their program exits, so give the
+		// user a chance to read them before the menu takes over the screen again
+		fmt.Print("\npress enter to return to the menu")
+		bufio.NewReader(os.Stdin).ReadString('\n')
+	}
+}
+
+func newMenuCmd() *cobra.Command {
+	var inputDir string
+	cmd := &cobra.Command{
+		Use:   "menu",
+		Short: "pick a day to run",
+		Long:  `pick a day, part and input to run from a menu`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runMenu(inputDir)
+		},
+	}
+
+	cmd.Flags().StringVar(&inputDir, "inputs", "inputs", "the directory with input files")
+
+	return cmd
+}
+
+func init() {
+	rootCmd.AddCommand(newMenuCmd())
+}
@@ -1,15 +1,21 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/sirgwain/advent-of-code-2024/advent"
+	"github.com/sirgwain/advent-of-code-2024/advent/color"
+	"github.com/sirgwain/advent-of-code-2024/advent/parse"
 	"github.com/spf13/cobra"
 )
 
 var logFile string
+var theme string
 
 func logPreRun(cmd *cobra.Command, args []string) error {
 	// log output to file
@@ -33,15 +39,33 @@ func logPreRun(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// themePreRun sets the color theme for all days
+func themePreRun(cmd *cobra.Command, args []string) error {
+	return advent.SetTheme(theme)
+}
+
+func rootPreRun(cmd *cobra.Command, args []string) error {
+	// once the flags are valid, later errors are about the input, not usage
+	if err := cmd.ValidateRequiredFlags(); err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+	if err := logPreRun(cmd, args); err != nil {
+		return err
+	}
+	return themePreRun(cmd, args)
+}
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:               "advent-of-code-2024",
 	Short:             "advent-of-code solutions for 2024",
-	PersistentPreRunE: logPreRun,
-	Run: func(cmd *cobra.Command, args []string) {
-		// Show usage
-		cmd.Help()
-		os.Exit(1)
+	PersistentPreRunE: rootPreRun,
+	// Execute prints errors so parse errors can point at the bad input
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// with no command, pick a day from the menu
+		return runMenu("inputs")
 	},
 }
 
@@ -50,7 +74,11 @@ var rootCmd = &cobra.Command{
 func Execute() {
 	err := rootCmd.Execute()
 	if err != nil {
-		println(err)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		var parseErr *parse.Error
+		if errors.As(err, &parseErr) {
+			fmt.Fprintf(os.Stderr, "\n%s\n", parseErr.Pointer())
+		}
 		os.Exit(1)
 	}
 }
@@ -58,4 +86,5 @@ func Execute() {
 func init() {
 	// all commands have debug mode
 	rootCmd.PersistentFlags().StringVarP(&logFile, "log", "", "tmp/advent.log", "log file to send structured logs to")
+	rootCmd.PersistentFlags().StringVar(&theme, "theme", color.Dark.Name, fmt.Sprintf("color theme, one of %s. NO_COLOR disables colors", strings.Join(color.ThemeNames(), ", ")))
 }
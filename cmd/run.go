@@ -1,21 +1,13 @@
 package cmd
 
 import (
-	"fmt"
-	"time"
+	"runtime"
 
 	"github.com/sirgwain/advent-of-code-2024/advent"
+	"github.com/sirgwain/advent-of-code-2024/advent/tui"
 	"github.com/spf13/cobra"
 )
 
-type dayRunner interface {
-	Run(part int, filename string, opts ...advent.Option) error
-}
-
-type dayVisualizer interface {
-	RunVisual(part int, filename string, opts ...advent.Option) error
-}
-
 func newRunCmd() *cobra.Command {
 	var day int
 	var part int
@@ -23,58 +15,50 @@ func newRunCmd() *cobra.Command {
 	var visualization bool
 	var redacted bool
 	var delay int
+	var render string
+	var redactPolicy string
+	var redactRatio float64
+	var redactDistance int
+	var workers int
+	var bigInt bool
+	var explain bool
+	var words []string
+	var dotFile string
 	cmd := &cobra.Command{
 		Use:   "run",
 		Short: "run a day",
 		Long:  `run the solution for a day`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			var runner dayRunner
-			switch day {
-			case 1:
-				runner = &advent.Day1{}
-			case 2:
-				runner = &advent.Day2{}
-			case 3:
-				runner = &advent.Day3{}
-			case 4:
-				runner = &advent.Day4{}
-			case 5:
-				runner = &advent.Day5{}
-			case 6:
-				runner = &advent.Day6{}
-			case 7:
-				runner = &advent.Day7{}
-			case 8:
-				runner = &advent.Day8{}
-			case 9:
-				runner = &advent.Day9{}
-			case 10:
-				runner = &advent.Day10{}
-			case 11:
-				runner = &advent.Day11{}
-			case 12:
-				runner = &advent.Day12{}
-			case 13:
-				runner = &advent.Day13{}
-			case 14:
-				runner = &advent.Day14{}
-			case 15:
-				runner = &advent.Day15{}
+			d, err := findDay(day)
+			if err != nil {
+				return err
 			}
 
-			if runner == nil {
-				return fmt.Errorf("day %d not found", day)
+			renderMode, err := tui.ParseRenderMode(render)
+			if err != nil {
+				return err
 			}
 
-			// run the visualizer if specified
-			if v, ok := runner.(dayVisualizer); ok && visualization {
-				return v.RunVisual(part, input, advent.WithDelay(delay), advent.WithRedactSolution(redacted))
+			policy, err := advent.ParseRedactPolicy(redactPolicy)
+			if err != nil {
+				return err
 			}
 
-			start := time.Now()
-			defer func() { fmt.Printf("\nTime taken %v\n", time.Since(start)) }()
+			opts := []advent.Option{
+				advent.WithDelay(delay),
+				advent.WithRedactSolution(redacted),
+				advent.WithRedactPolicy(policy),
+				advent.WithRedactRatio(redactRatio),
+				advent.WithRedactDistance(redactDistance),
+				advent.WithRenderMode(renderMode),
+				advent.WithWorkers(workers),
+				advent.WithBigInt(bigInt),
+				advent.WithExplain(explain),
+				advent.WithWords(words),
+				advent.WithDotFile(dotFile),
+			}
 
-			return runner.Run(part, input, advent.WithDelay(delay), advent.WithRedactSolution(redacted))
+			return d.run(part, input, visualization, opts...)
 		},
 	}
 
@@ -84,6 +68,17 @@ func newRunCmd() *cobra.Command {
 	cmd.Flags().IntVar(&delay, "delay", 0, "a delay, in ms to add to the UI")
 	cmd.Flags().BoolVarP(&visualization, "visualization", "v", false, "run the visualization for this day, if available")
 	cmd.Flags().BoolVar(&redacted, "redacted", false, "hide the solution")
+	cmd.Flags().StringVar(&redactPolicy, "redact-policy", "ratio", "when to hide a redacted solution: always, ratio, distance or final")
+	cmd.Flags().Float64Var(&redactRatio, "redact-ratio", .75, "with the ratio policy, hide the solution once it's past this fraction of the answer")
+	cmd.Flags().IntVar(&redactDistance, "redact-distance", 100, "with the distance policy, hide the solution once it's within this distance of the answer")
+	cmd.Flags().StringVar(&render, "render", "auto", "how to draw large boards: auto, full, half or braille")
+
+	cmd.Flags().IntVar(&workers, "workers", runtime.GOMAXPROCS(0), "how many workers to use for parts that run in parallel")
+
+	cmd.Flags().BoolVar(&bigInt, "bigint", false, "use big integers for answers that can overflow (days 7, 11 and 13), instead of only when they overflow")
+	cmd.Flags().BoolVar(&explain, "explain", false, "explain the verdict for every report (day 2) or trace every instruction (day 3)")
+	cmd.Flags().StringSliceVar(&words, "words", nil, "words or patterns with rows separated by / and . wildcards to find instead of XMAS (day 4), like SANTA or M.M/.A./S.S")
+	cmd.Flags().StringVar(&dotFile, "dot", "", "write the ordering rules as a Graphviz DOT graph to this file, with broken rules in red (day 5)")
 
 	cmd.MarkFlagRequired("day")
 	cmd.MarkFlagRequired("input")
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func newValidateCmd() *cobra.Command {
+	var day int
+	var input string
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "check an input file",
+		Long:  `check that an input file is in the format a day expects, without solving it`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			d, err := findDay(day)
+			if err != nil {
+				return err
+			}
+
+			if err := d.validate(input); err != nil {
+				return err
+			}
+
+			fmt.Printf("%s is a valid input for day %d\n", input, d.number)
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVarP(&day, "day", "d", 0, "the day the input is for")
+	cmd.Flags().StringVarP(&input, "input", "i", "", "the input file to check")
+
+	cmd.MarkFlagRequired("day")
+	cmd.MarkFlagRequired("input")
+
+	return cmd
+}
+
+func init() {
+	rootCmd.AddCommand(newValidateCmd())
+}